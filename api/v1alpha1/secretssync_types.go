@@ -5,6 +5,8 @@ Copyright 2025 Edenlab
 package v1alpha1
 
 import (
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -12,18 +14,277 @@ import (
 // NOTE: json tags are required.  Any new fields you add must have json tags for the fields to be serialized.
 
 type SrcSecret struct {
+	// Kind of the source object
+	// +kubebuilder:validation:Enum=Secret;ConfigMap
+	// +kubebuilder:default=Secret
+	// +optional
+	Kind         string      `json:"kind,omitempty"`
 	SrcNamespace string      `json:"srcNamespace"`
 	DstSecrets   []DstSecret `json:"dstSecrets,omitempty"`
+	// Optional sources do not change the phase of the SecretsSync while they are missing
+	// +optional
+	Optional bool `json:"optional,omitempty"`
 }
 
 type DstSecret struct {
-	Name string            `json:"name,omitempty"`
+	// Name of the destination, defaults to the source name. It may be a template of .SourceName,
+	// .SourceNamespace, .SourceKind and .Namespace (the destination namespace), e.g. {{.SourceNamespace}}-{{.SourceName}}
+	// +kubebuilder:validation:MaxLength=253
+	// +optional
+	Name string `json:"name,omitempty"`
+	// Kind of the destination object, defaults to the kind of the source
+	// +kubebuilder:validation:Enum=Secret;ConfigMap
+	// +optional
+	Kind string `json:"kind,omitempty"`
+	// Keys renames the source keys, a key:$.json.path source extracts a field of the JSON or YAML document of the key,
+	// the document itself is only copied when it is renamed as well
+	// +optional
 	Keys map[string]string `json:"keys,omitempty"`
+	// RequiredKeys are the destination keys after the keys mapping which have to be present,
+	// otherwise the destination fails and the previously synced one is kept
+	// +optional
+	RequiredKeys []string `json:"requiredKeys,omitempty"`
+	// KeyMappings copy a source key to several destination keys
+	// +optional
+	KeyMappings []KeyMapping `json:"keyMappings,omitempty"`
+	// Flatten lists the source keys with JSON or YAML documents which are replaced by a key per field
+	// named by the dotted path of the field, e.g. database.password
+	// +optional
+	Flatten []string `json:"flatten,omitempty"`
+	// When is a CEL predicate over the source (name, srcNamespace, secretType, labels, annotations, data),
+	// the destination is only produced when it returns true, e.g. "'password' in data && labels.env == 'prod'"
+	// +kubebuilder:validation:MaxLength=4096
+	// +optional
+	When string `json:"when,omitempty"`
+	// ComputedKeys maps the destination keys to CEL expressions over the source returning a string or bytes,
+	// e.g. "'postgres://' + string(data.username) + '@db:5432'"
+	// +optional
+	ComputedKeys map[string]string `json:"computedKeys,omitempty"`
+	// Transforms of the values applied in order after the keys mapping
+	// +optional
+	Transforms []KeyTransform `json:"transforms,omitempty"`
+	// Bundle writes the keys into a single file key after the transforms
+	// +optional
+	Bundle *Bundle `json:"bundle,omitempty"`
+	// Steps lists the named transformers of the registry applied in order after the transforms and the bundle
+	// +optional
+	Steps []TransformerStep `json:"steps,omitempty"`
+	// Namespaces to create the destination in, defaults to the namespace of the SecretsSync
+	// +optional
+	Namespaces []string `json:"namespaces,omitempty"`
+	// ImagePullServiceAccounts lists the names or glob patterns ("*" for all) of the service accounts
+	// in the destination namespaces which get the secret in imagePullSecrets
+	// +optional
+	ImagePullServiceAccounts []string `json:"imagePullServiceAccounts,omitempty"`
+	// Format converts the destination keys, dockerconfigjson assembles a .dockerconfigjson key
+	// of the kubernetes.io/dockerconfigjson type from the registries credentials
+	// +kubebuilder:validation:Enum=dockerconfigjson
+	// +optional
+	Format string `json:"format,omitempty"`
+	// Registries of the dockerconfigjson format, a single registry with the default keys is used when it is empty
+	// +optional
+	Registries []Registry `json:"registries,omitempty"`
+	// Type overrides the type of the source secret, the keys required by the built-in types are validated.
+	// It is rejected for a ConfigMap destination.
+	// +optional
+	Type v1.SecretType `json:"type,omitempty"`
+	// Keystore adds the Java keystores converted from the PEM tls.crt, tls.key and ca.crt keys
+	// +optional
+	Keystore *Keystore `json:"keystore,omitempty"`
+	// Mode Replace recreates the destination with the generated keys only, Merge applies the generated keys
+	// with server-side apply and leaves the keys of other tools untouched, the merged destination is never deleted,
+	// only its keys are removed
+	// +kubebuilder:validation:Enum=Replace;Merge
+	// +optional
+	Mode string `json:"mode,omitempty"`
+	// Immutable creates the destination as an immutable object
+	// +optional
+	Immutable bool `json:"immutable,omitempty"`
+	// ReplaceStrategy of an immutable destination when its content changes, Recreate deletes and creates it,
+	// Keep keeps the existing object and reports it in the ImmutableConflict condition
+	// +kubebuilder:validation:Enum=Recreate;Keep
+	// +optional
+	ReplaceStrategy string `json:"replaceStrategy,omitempty"`
+	// Propagate selects the labels and annotations of the source copied to the destination
+	// +optional
+	Propagate *MetadataPropagation `json:"propagate,omitempty"`
+	// Labels are added to the destination on top of the propagated ones
+	// +optional
+	Labels map[string]string `json:"labels,omitempty"`
+	// Annotations are added to the destination on top of the propagated ones
+	// +optional
+	Annotations map[string]string `json:"annotations,omitempty"`
+}
+
+// MetadataPropagation selects the source labels and annotations copied to the destination,
+// the internal.edenlab.io/ keys are never copied
+type MetadataPropagation struct {
+	// +optional
+	Labels *MetadataFilter `json:"labels,omitempty"`
+	// +optional
+	Annotations *MetadataFilter `json:"annotations,omitempty"`
+}
+
+// MetadataFilter selects the keys by names or prefixes ending with "*", e.g. app.kubernetes.io/*
+type MetadataFilter struct {
+	// All copies all keys except the denied ones
+	// +optional
+	All bool `json:"all,omitempty"`
+	// Allow lists the copied keys when All is not set
+	// +optional
+	Allow []string `json:"allow,omitempty"`
+	// Deny lists the keys which are never copied
+	// +optional
+	Deny []string `json:"deny,omitempty"`
+}
+
+// KeyMapping copies the value of a source key to the destination keys
+type KeyMapping struct {
+	// From is the source key
+	From string `json:"from"`
+	// To lists the destination keys, they must not conflict with the copied or renamed keys
+	// +kubebuilder:validation:MinItems=1
+	To []string `json:"to"`
+	// KeepOriginal keeps the source key under its own name as well
+	// +optional
+	KeepOriginal bool `json:"keepOriginal,omitempty"`
+	// Default is the value of the destination keys when the source key is missing
+	// +optional
+	Default *string `json:"default,omitempty"`
+}
+
+// KeyTransform applies the operations in order to the values of the destination keys
+type KeyTransform struct {
+	// Keys are the destination keys after renaming, all keys when it is empty
+	// +optional
+	Keys []string `json:"keys,omitempty"`
+	// +kubebuilder:validation:MinItems=1
+	Operations []TransformOperation `json:"operations"`
+}
+
+// +kubebuilder:validation:Enum=base64Decode;base64Encode;trimSpace;hexEncode;hexDecode
+type TransformOperation string
+
+// Bundle defines a single file key holding the values of the destination keys
+type Bundle struct {
+	// Key of the file, e.g. app.env
+	Key string `json:"key"`
+	// Format of the file, env writes KEY=value lines, properties writes Java properties, json writes an object
+	// +kubebuilder:validation:Enum=env;json;properties
+	Format string `json:"format"`
+	// Keys written to the file, all keys when it is empty
+	// +optional
+	Keys []string `json:"keys,omitempty"`
+	// KeepKeys keeps the bundled keys next to the file
+	// +optional
+	KeepKeys bool `json:"keepKeys,omitempty"`
+}
+
+// TransformerStep invokes a transformer registered in the operator
+type TransformerStep struct {
+	// Name of the transformer, e.g. base64Decode or bundle
+	Name string `json:"name"`
+	// Params of the transformer, e.g. keys with the comma separated list of the keys
+	// +optional
+	Params map[string]string `json:"params,omitempty"`
+}
+
+// Keystore defines the conversion of the PEM certificates to Java keystores, the keystore is built
+// from tls.key and tls.crt, the truststore from ca.crt, the keys refer to the destination keys after renaming
+type Keystore struct {
+	// Formats of the keystores, pkcs12 adds the keystore.p12 and truststore.p12 keys,
+	// jks adds the keystore.jks and truststore.jks keys
+	// +kubebuilder:validation:MinItems=1
+	Formats []KeystoreFormat `json:"formats"`
+	// Alias of the private key entry of the JKS keystore, the PKCS#12 key entry has no friendly name
+	// +kubebuilder:default=tls
+	// +optional
+	Alias string `json:"alias,omitempty"`
+	// PasswordKey holds the keystore password, when the key is absent a password is derived from tls.key
+	// and stored in it. It is required when only ca.crt is present.
+	// +kubebuilder:default=keystore-password
+	// +optional
+	PasswordKey string `json:"passwordKey,omitempty"`
+}
+
+// +kubebuilder:validation:Enum=pkcs12;jks
+type KeystoreFormat string
+
+// Registry defines the credentials of a single registry of the dockerconfigjson format,
+// the keys refer to the destination keys after renaming
+type Registry struct {
+	// Server is the registry address, the value of ServerKey is used when it is not set
+	// +optional
+	Server string `json:"server,omitempty"`
+	// ServerKey defaults to server
+	// +optional
+	ServerKey string `json:"serverKey,omitempty"`
+	// UsernameKey defaults to username
+	// +optional
+	UsernameKey string `json:"usernameKey,omitempty"`
+	// PasswordKey defaults to password
+	// +optional
+	PasswordKey string `json:"passwordKey,omitempty"`
+	// +optional
+	EmailKey string `json:"emailKey,omitempty"`
 }
 
 // SecretsSyncSpec defines the desired state of SecretsSync
 type SecretsSyncSpec struct {
 	Secrets map[string]SrcSecret `json:"secrets"`
+	// RefreshInterval is the period between two consecutive syncs of the source secrets,
+	// the manager --refresh-interval flag is used when it is not set.
+	// +optional
+	RefreshInterval *metav1.Duration `json:"refreshInterval,omitempty"`
+	// DryRun disables any changes of the destination secrets, the intended changes are reported in status.plan.
+	// +optional
+	DryRun bool `json:"dryRun,omitempty"`
+	// CertificateExpiryWindow is the time before notAfter of a synced certificate when the CertificateExpiring
+	// condition is raised, the manager --certificate-expiry-window flag is used when it is not set.
+	// +optional
+	CertificateExpiryWindow *metav1.Duration `json:"certificateExpiryWindow,omitempty"`
+	// Limits of the generated destinations, the lower of them and the manager limits is enforced
+	// +optional
+	Limits *Limits `json:"limits,omitempty"`
+}
+
+// Limits of the generated destinations, the destinations are not written when they are exceeded
+type Limits struct {
+	// MaxDestinations is the number of destinations of all sources
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	MaxDestinations *int32 `json:"maxDestinations,omitempty"`
+	// MaxBytes is the size of the keys and values of a single destination
+	// +optional
+	MaxBytes *resource.Quantity `json:"maxBytes,omitempty"`
+	// MaxKeys is the number of keys of a single destination
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	MaxKeys *int32 `json:"maxKeys,omitempty"`
+}
+
+// PlannedChange is a change of a destination secret which would be applied without dry-run
+type PlannedChange struct {
+	// Action is one of Create, Update, Recreate, Delete or Release
+	Action string `json:"action"`
+	// Kind is Secret or ConfigMap
+	Kind      string   `json:"kind"`
+	Name      string   `json:"name"`
+	Namespace string   `json:"namespace"`
+	Keys      []string `json:"keys,omitempty"`
+	// Hash is the HMAC-SHA256 of the secret data keyed by the operator, the values themselves are never reported
+	Hash string `json:"hash,omitempty"`
+}
+
+// CertificateStatus describes the first PEM certificate of a key of a synced destination secret
+type CertificateStatus struct {
+	Name      string      `json:"name"`
+	Namespace string      `json:"namespace"`
+	Key       string      `json:"key"`
+	NotAfter  metav1.Time `json:"notAfter"`
+	Issuer    string      `json:"issuer"`
+	// SANs are the DNS names, IP addresses, emails and URIs of the certificate
+	SANs []string `json:"sans,omitempty"`
 }
 
 // SecretsSyncStatus defines the observed state of SecretsSync
@@ -35,6 +296,15 @@ type SecretsSyncStatus struct {
 	Error       string       `json:"error,omitempty"`
 	Phase       string       `json:"phase,omitempty"`
 	Count       int          `json:"count"`
+	// Plan lists the changes which would be applied if dry-run was disabled
+	Plan []PlannedChange `json:"plan,omitempty"`
+	// Certificates lists the certificates found in the synced secrets
+	Certificates []CertificateStatus `json:"certificates,omitempty"`
+	// Conditions represent the latest observations of the SecretsSync state
+	// +listType=map
+	// +listMapKey=type
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
 }
 
 //+kubebuilder:object:root=true
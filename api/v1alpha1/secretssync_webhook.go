@@ -0,0 +1,200 @@
+/*
+Copyright 2025 Edenlab
+*/
+
+package v1alpha1
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	apivalidation "k8s.io/apimachinery/pkg/api/validation"
+	metav1validation "k8s.io/apimachinery/pkg/apis/meta/v1/validation"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+
+	"secrets-sync.operators.infra/internal/expression"
+	"secrets-sync.operators.infra/internal/naming"
+	"secrets-sync.operators.infra/internal/transformer"
+)
+
+// log is for logging in this package.
+var secretssynclog = logf.Log.WithName("secretssync-resource")
+
+func (r *SecretsSync) SetupWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(r).
+		Complete()
+}
+
+//+kubebuilder:webhook:path=/validate-internal-edenlab-io-v1alpha1-secretssync,mutating=false,failurePolicy=fail,sideEffects=None,groups=internal.edenlab.io,resources=secretssyncs,verbs=create;update,versions=v1alpha1,name=vsecretssync.kb.io,admissionReviewVersions=v1
+
+var _ webhook.Validator = &SecretsSync{}
+
+// ValidateCreate implements webhook.Validator so a webhook will be registered for the type
+func (r *SecretsSync) ValidateCreate() error {
+	secretssynclog.Info("validate create", "name", r.Name)
+
+	return r.validateSecretsSync()
+}
+
+// ValidateUpdate implements webhook.Validator so a webhook will be registered for the type
+func (r *SecretsSync) ValidateUpdate(old runtime.Object) error {
+	secretssynclog.Info("validate update", "name", r.Name)
+
+	return r.validateSecretsSync()
+}
+
+// ValidateDelete implements webhook.Validator so a webhook will be registered for the type
+func (r *SecretsSync) ValidateDelete() error {
+	return nil
+}
+
+func (r *SecretsSync) validateSecretsSync() error {
+	allErrs := ValidateSpec(&r.Spec, field.NewPath("spec"))
+	if len(allErrs) == 0 {
+		return nil
+	}
+
+	return apierrors.NewInvalid(GroupVersion.WithKind("SecretsSync").GroupKind(), r.Name, allErrs)
+}
+
+// ValidateSpec checks the name templates, the extra metadata, the modes, the types, the key mappings and the transformer steps
+// and type-checks the CEL expressions of the destinations
+func ValidateSpec(spec *SecretsSyncSpec, path *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+
+	names := make([]string, 0, len(spec.Secrets))
+	for name := range spec.Secrets {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	for _, name := range names {
+		for i, dstSecret := range spec.Secrets[name].DstSecrets {
+			dstPath := path.Child("secrets").Key(name).Child("dstSecrets").Index(i)
+
+			if naming.IsTemplate(dstSecret.Name) {
+				if err := naming.Parse(dstSecret.Name); err != nil {
+					allErrs = append(allErrs, field.Invalid(dstPath.Child("name"), dstSecret.Name, err.Error()))
+				}
+			}
+
+			if len(dstSecret.When) > 0 {
+				if err := expression.CheckPredicate(dstSecret.When); err != nil {
+					allErrs = append(allErrs, field.Invalid(dstPath.Child("when"), dstSecret.When, err.Error()))
+				}
+			}
+
+			allErrs = append(allErrs, validateMetadata(dstSecret, dstPath)...)
+
+			// The merged objects are shared with other tools, which have to be able to update them
+			if dstSecret.Immutable && dstSecret.Mode == "Merge" {
+				allErrs = append(allErrs, field.Forbidden(dstPath.Child("immutable"), "not supported with the Merge mode"))
+			}
+
+			// The config maps have no type, it would be silently dropped
+			if len(dstSecret.Type) > 0 && destinationKind(spec.Secrets[name], dstSecret) == "ConfigMap" {
+				allErrs = append(allErrs, field.Forbidden(dstPath.Child("type"), "not supported for a ConfigMap destination"))
+			}
+
+			allErrs = append(allErrs, validateKeyMappings(dstSecret.KeyMappings, dstPath.Child("keyMappings"))...)
+
+			for j, step := range dstSecret.Steps {
+				if _, ok := transformer.Get(step.Name); !ok {
+					allErrs = append(allErrs, field.NotSupported(dstPath.Child("steps").Index(j).Child("name"),
+						step.Name, transformer.Names()))
+				}
+			}
+
+			keys := make([]string, 0, len(dstSecret.ComputedKeys))
+			for key := range dstSecret.ComputedKeys {
+				keys = append(keys, key)
+			}
+
+			sort.Strings(keys)
+
+			for _, key := range keys {
+				if err := expression.CheckValue(dstSecret.ComputedKeys[key]); err != nil {
+					allErrs = append(allErrs, field.Invalid(dstPath.Child("computedKeys").Key(key),
+						dstSecret.ComputedKeys[key], err.Error()))
+				}
+			}
+		}
+	}
+
+	return allErrs
+}
+
+// destinationKind returns the kind of the destination object, by default it matches the kind of the source
+func destinationKind(srcSecret SrcSecret, dstSecret DstSecret) string {
+	if len(dstSecret.Kind) > 0 {
+		return dstSecret.Kind
+	}
+
+	if srcSecret.Kind == "ConfigMap" {
+		return "ConfigMap"
+	}
+
+	return "Secret"
+}
+
+// validateKeyMappings checks that the destination keys of the mappings are valid and distinct secret keys
+func validateKeyMappings(mappings []KeyMapping, path *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+
+	seen := make(map[string]bool)
+	for i, mapping := range mappings {
+		for j, name := range mapping.To {
+			toPath := path.Index(i).Child("to").Index(j)
+
+			if errs := validation.IsConfigMapKey(name); len(errs) > 0 {
+				allErrs = append(allErrs, field.Invalid(toPath, name, strings.Join(errs, ", ")))
+				continue
+			}
+
+			if seen[name] {
+				allErrs = append(allErrs, field.Duplicate(toPath, name))
+			}
+
+			seen[name] = true
+		}
+	}
+
+	return allErrs
+}
+
+// validateMetadata checks the extra labels and annotations, the internal keys are reserved for the operator
+func validateMetadata(dstSecret DstSecret, path *field.Path) field.ErrorList {
+	allErrs := metav1validation.ValidateLabels(dstSecret.Labels, path.Child("labels"))
+	allErrs = append(allErrs, apivalidation.ValidateAnnotations(dstSecret.Annotations, path.Child("annotations"))...)
+
+	reserved := GroupVersion.Group + "/"
+	for _, child := range []struct {
+		name     string
+		metadata map[string]string
+	}{{"labels", dstSecret.Labels}, {"annotations", dstSecret.Annotations}} {
+		keys := make([]string, 0, len(child.metadata))
+		for key := range child.metadata {
+			keys = append(keys, key)
+		}
+
+		sort.Strings(keys)
+
+		for _, key := range keys {
+			if strings.HasPrefix(key, reserved) {
+				allErrs = append(allErrs, field.Forbidden(path.Child(child.name).Key(key),
+					fmt.Sprintf("the %s keys are reserved", reserved)))
+			}
+		}
+	}
+
+	return allErrs
+}
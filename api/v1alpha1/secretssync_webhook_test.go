@@ -0,0 +1,79 @@
+/*
+Copyright 2025 Edenlab
+*/
+
+package v1alpha1
+
+import (
+	"testing"
+
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+func TestValidateSpec(t *testing.T) {
+	tests := []struct {
+		name      string
+		dstSecret DstSecret
+		wantField string
+	}{
+		{
+			name:      "registered steps",
+			dstSecret: DstSecret{Steps: []TransformerStep{{Name: "trimSpace"}, {Name: "bundle", Params: map[string]string{"key": ".env"}}}},
+		},
+		{
+			name:      "unknown step",
+			dstSecret: DstSecret{Steps: []TransformerStep{{Name: "trimSpace"}, {Name: "upperCase"}}},
+			wantField: "spec.secrets[app].dstSecrets[0].steps[1].name",
+		},
+		{
+			name: "key mappings",
+			dstSecret: DstSecret{KeyMappings: []KeyMapping{
+				{From: "password", To: []string{"DB_PASSWORD", "db.password"}},
+				{From: "user", To: []string{"DB_USER"}},
+			}},
+		},
+		{
+			name:      "invalid key mapping name",
+			dstSecret: DstSecret{KeyMappings: []KeyMapping{{From: "password", To: []string{"DB_PASSWORD", "db/password"}}}},
+			wantField: "spec.secrets[app].dstSecrets[0].keyMappings[0].to[1]",
+		},
+		{
+			name: "duplicate key mapping name",
+			dstSecret: DstSecret{KeyMappings: []KeyMapping{
+				{From: "password", To: []string{"SECRET"}},
+				{From: "token", To: []string{"SECRET"}},
+			}},
+			wantField: "spec.secrets[app].dstSecrets[0].keyMappings[1].to[0]",
+		},
+		{
+			name:      "typed secret",
+			dstSecret: DstSecret{Type: "kubernetes.io/basic-auth"},
+		},
+		{
+			name:      "typed config map",
+			dstSecret: DstSecret{Kind: "ConfigMap", Type: "kubernetes.io/basic-auth"},
+			wantField: "spec.secrets[app].dstSecrets[0].type",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			spec := &SecretsSyncSpec{Secrets: map[string]SrcSecret{
+				"app": {SrcNamespace: "platform", DstSecrets: []DstSecret{tt.dstSecret}},
+			}}
+
+			errs := ValidateSpec(spec, field.NewPath("spec"))
+			if len(tt.wantField) == 0 {
+				if len(errs) > 0 {
+					t.Errorf("ValidateSpec() = %v, want no errors", errs)
+				}
+
+				return
+			}
+
+			if len(errs) != 1 || errs[0].Field != tt.wantField {
+				t.Errorf("ValidateSpec() = %v, want a single error of %s", errs, tt.wantField)
+			}
+		})
+	}
+}
@@ -0,0 +1,55 @@
+/*
+Copyright 2025 Edenlab
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// SecretsSyncProfileSpec defines the desired state of SecretsSyncProfile
+type SecretsSyncProfileSpec struct {
+	// SecretsSyncName is the name of the SecretsSync created in the namespaces using the profile,
+	// defaults to the name of the profile
+	// +optional
+	SecretsSyncName string `json:"secretsSyncName,omitempty"`
+	// Template is the spec of the SecretsSync created in the namespaces using the profile. It is copied
+	// as is, the destinations without namespaces are created in the namespace of the SecretsSync and
+	// the name templates see it as .Namespace.
+	Template SecretsSyncSpec `json:"template"`
+}
+
+// SecretsSyncProfileStatus defines the observed state of SecretsSyncProfile
+type SecretsSyncProfileStatus struct {
+	// Namespaces using the profile
+	Namespaces []string `json:"namespaces,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+//+kubebuilder:resource:scope=Cluster
+
+// SecretsSyncProfile is the Schema for the secretssyncprofiles API.
+// A SecretsSync is instantiated from the profile in every namespace labeled
+// with internal.edenlab.io/secrets-profile=<profile name>.
+type SecretsSyncProfile struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   SecretsSyncProfileSpec   `json:"spec,omitempty"`
+	Status SecretsSyncProfileStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// SecretsSyncProfileList contains a list of SecretsSyncProfile
+type SecretsSyncProfileList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []SecretsSyncProfile `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&SecretsSyncProfile{}, &SecretsSyncProfileList{})
+}
@@ -0,0 +1,66 @@
+/*
+Copyright 2025 Edenlab
+*/
+
+package v1alpha1
+
+import (
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+)
+
+// log is for logging in this package.
+var secretssyncprofilelog = logf.Log.WithName("secretssyncprofile-resource")
+
+func (r *SecretsSyncProfile) SetupWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(r).
+		Complete()
+}
+
+//+kubebuilder:webhook:path=/validate-internal-edenlab-io-v1alpha1-secretssyncprofile,mutating=false,failurePolicy=fail,sideEffects=None,groups=internal.edenlab.io,resources=secretssyncprofiles,verbs=create;update,versions=v1alpha1,name=vsecretssyncprofile.kb.io,admissionReviewVersions=v1
+
+var _ webhook.Validator = &SecretsSyncProfile{}
+
+// ValidateCreate implements webhook.Validator so a webhook will be registered for the type
+func (r *SecretsSyncProfile) ValidateCreate() error {
+	secretssyncprofilelog.Info("validate create", "name", r.Name)
+
+	return r.validateSecretsSyncProfile()
+}
+
+// ValidateUpdate implements webhook.Validator so a webhook will be registered for the type
+func (r *SecretsSyncProfile) ValidateUpdate(old runtime.Object) error {
+	secretssyncprofilelog.Info("validate update", "name", r.Name)
+
+	return r.validateSecretsSyncProfile()
+}
+
+// ValidateDelete implements webhook.Validator so a webhook will be registered for the type
+func (r *SecretsSyncProfile) ValidateDelete() error {
+	return nil
+}
+
+// validateSecretsSyncProfile checks the template like the spec of a SecretsSync, so that an invalid profile
+// is rejected before it is instantiated in every labeled namespace
+func (r *SecretsSyncProfile) validateSecretsSyncProfile() error {
+	path := field.NewPath("spec")
+
+	allErrs := ValidateSpec(&r.Spec.Template, path.Child("template"))
+	if len(r.Spec.SecretsSyncName) > 0 {
+		for _, msg := range validation.IsDNS1123Subdomain(r.Spec.SecretsSyncName) {
+			allErrs = append(allErrs, field.Invalid(path.Child("secretsSyncName"), r.Spec.SecretsSyncName, msg))
+		}
+	}
+
+	if len(allErrs) == 0 {
+		return nil
+	}
+
+	return apierrors.NewInvalid(GroupVersion.WithKind("SecretsSyncProfile").GroupKind(), r.Name, allErrs)
+}
@@ -0,0 +1,34 @@
+/*
+Copyright 2025 Edenlab
+*/
+
+package v1alpha1
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestValidateSecretsSyncProfile(t *testing.T) {
+	profile := func(secretsSyncName, when string) *SecretsSyncProfile {
+		return &SecretsSyncProfile{Spec: SecretsSyncProfileSpec{
+			SecretsSyncName: secretsSyncName,
+			Template: SecretsSyncSpec{Secrets: map[string]SrcSecret{
+				"mongodb": {SrcNamespace: "mongodb", DstSecrets: []DstSecret{{Name: "{{.Namespace}}-mongodb", When: when}}},
+			}},
+		}}
+	}
+
+	if err := profile("preview-secrets", "'password' in data").ValidateCreate(); err != nil {
+		t.Errorf("ValidateCreate() of a valid profile error = %v", err)
+	}
+
+	err := profile("preview-secrets", "data.password").ValidateCreate()
+	if err == nil || !strings.Contains(err.Error(), "spec.template.secrets[mongodb].dstSecrets[0].when") {
+		t.Errorf("ValidateCreate() error = %v, want the invalid when of the template", err)
+	}
+
+	if err := profile("Preview_Secrets", "").ValidateUpdate(nil); err == nil || !strings.Contains(err.Error(), "spec.secretsSyncName") {
+		t.Errorf("ValidateUpdate() error = %v, want the invalid SecretsSync name", err)
+	}
+}
@@ -11,6 +11,7 @@ import (
 	"os"
 	"sigs.k8s.io/controller-runtime/pkg/cache"
 	"strings"
+	"time"
 
 	// Import all Kubernetes client auth plugins (e.g. Azure, GCP, OIDC, etc.)
 	// to ensure that exec-entrypoint and run can make use of them.
@@ -43,11 +44,40 @@ func main() {
 	var metricsAddr string
 	var enableLeaderElection bool
 	var probeAddr string
+	var refreshInterval time.Duration
+	var maxBackoff time.Duration
+	var dryRun bool
+	var certificateExpiryWindow time.Duration
+	var maxDestinations int
+	var maxDestinationBytes int64
+	var maxDestinationKeys int
+	var allowedDestinationNamespaces string
+	var planHashKeyFile string
 	flag.StringVar(&metricsAddr, "metrics-bind-address", ":8080", "The address the metric endpoint binds to.")
 	flag.StringVar(&probeAddr, "health-probe-bind-address", ":8081", "The address the probe endpoint binds to.")
 	flag.BoolVar(&enableLeaderElection, "leader-elect", false,
 		"Enable leader election for controller manager. "+
 			"Enabling this will ensure there is only one active controller manager.")
+	flag.DurationVar(&refreshInterval, "refresh-interval", time.Second*3,
+		"The default interval between syncs of a SecretsSync without spec.refreshInterval.")
+	flag.DurationVar(&maxBackoff, "max-backoff", time.Minute*5,
+		"The maximum delay between retries of a SecretsSync with missing sources or failed API calls.")
+	flag.BoolVar(&dryRun, "dry-run", false,
+		"Only report the intended changes in the status of every SecretsSync without writing secrets.")
+	flag.DurationVar(&certificateExpiryWindow, "certificate-expiry-window", time.Hour*24*30,
+		"The default time before expiration of a synced certificate when the CertificateExpiring condition is raised.")
+	flag.IntVar(&maxDestinations, "max-destinations", 0,
+		"The maximum number of destinations of a SecretsSync or replicas of a Secret, 0 is unlimited.")
+	flag.Int64Var(&maxDestinationBytes, "max-destination-bytes", 1024*1024,
+		"The maximum size of the keys and values of a single destination.")
+	flag.IntVar(&maxDestinationKeys, "max-destination-keys", 0,
+		"The maximum number of keys of a single destination, 0 is unlimited.")
+	flag.StringVar(&allowedDestinationNamespaces, "allowed-destination-namespaces", "",
+		"The comma separated names or glob patterns of the namespaces which accept secrets from all namespaces, "+
+			"other namespaces have to list the source namespaces in the internal.edenlab.io/allowed-namespaces annotation.")
+	flag.StringVar(&planHashKeyFile, "plan-hash-key-file", "",
+		"The file holding the key of the data hashes in the dry-run plans, at least 32 bytes. "+
+			"Without it a random key is generated and the hashes change on every restart.")
 	opts := zap.Options{Development: true, StacktraceLevel: zapcore.PanicLevel}
 	opts.BindFlags(flag.CommandLine)
 	flag.Parse()
@@ -95,14 +125,74 @@ func main() {
 		os.Exit(1)
 	}
 
+	accessPolicy := controller.NewAccessPolicy(allowedDestinationNamespaces)
+
+	planHashKey, err := controller.LoadPlanHashKey(planHashKeyFile)
+	if err != nil {
+		setupLog.Error(err, "unable to load the plan hash key")
+		os.Exit(1)
+	}
+
 	if err = (&controller.SecretsSyncReconciler{
-		Client:     mgr.GetClient(),
-		Scheme:     mgr.GetScheme(),
-		SystemInfo: &controller.SystemInfo{},
+		Client:                  mgr.GetClient(),
+		Scheme:                  mgr.GetScheme(),
+		SystemInfo:              &controller.SystemInfo{},
+		RefreshInterval:         refreshInterval,
+		MaxBackoff:              maxBackoff,
+		DryRun:                  dryRun,
+		CertificateExpiryWindow: certificateExpiryWindow,
+		Recorder:                mgr.GetEventRecorderFor("secretssync-controller"),
+		MaxDestinations:         maxDestinations,
+		MaxDestinationBytes:     maxDestinationBytes,
+		MaxDestinationKeys:      maxDestinationKeys,
+		AccessPolicy:            accessPolicy,
+		PlanHashKey:             planHashKey,
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "SecretsSync")
 		os.Exit(1)
 	}
+
+	if err = (&controller.SecretReplicatorReconciler{
+		Client:              mgr.GetClient(),
+		Scheme:              mgr.GetScheme(),
+		AccessPolicy:        accessPolicy,
+		MaxDestinations:     maxDestinations,
+		MaxDestinationBytes: maxDestinationBytes,
+		MaxDestinationKeys:  maxDestinationKeys,
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "SecretReplicator")
+		os.Exit(1)
+	}
+
+	if err = (&controller.SecretReflectorReconciler{
+		Client:       mgr.GetClient(),
+		Scheme:       mgr.GetScheme(),
+		AccessPolicy: accessPolicy,
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "SecretReflector")
+		os.Exit(1)
+	}
+
+	if err = (&controller.NamespaceReconciler{
+		Client: mgr.GetClient(),
+		Scheme: mgr.GetScheme(),
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "Namespace")
+		os.Exit(1)
+	}
+
+	// The webhook needs a serving certificate, it is enabled by the [WEBHOOK] sections of config/default
+	if os.Getenv("ENABLE_WEBHOOKS") == "true" {
+		if err = (&internalv1alpha1.SecretsSync{}).SetupWebhookWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "SecretsSync")
+			os.Exit(1)
+		}
+
+		if err = (&internalv1alpha1.SecretsSyncProfile{}).SetupWebhookWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "SecretsSyncProfile")
+			os.Exit(1)
+		}
+	}
 	//+kubebuilder:scaffold:builder
 
 	if err := mgr.AddHealthzCheck("healthz", healthz.Ping); err != nil {
@@ -0,0 +1,75 @@
+/*
+Copyright 2025 Edenlab
+*/
+
+package controller
+
+import (
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/util/workqueue"
+)
+
+const (
+	defaultRefreshInterval = time.Second * 3
+	defaultMaxBackoff      = time.Minute * 5
+	// minRefreshInterval keeps the short refresh intervals from turning into a busy loop
+	minRefreshInterval = time.Second
+	jitterFactor       = 0.2
+)
+
+// failureBackoff counts consecutive unsuccessful syncs per object
+// and calculates an exponentially growing requeue delay with jitter.
+type failureBackoff struct {
+	mu       sync.Mutex
+	failures map[types.NamespacedName]int
+}
+
+// next returns base*2^n for the n-th consecutive failure with jitter, capped at maxDelay.
+// The delay never drops below base, so a refresh interval longer than maxDelay is kept.
+func (b *failureBackoff) next(key types.NamespacedName, base, maxDelay time.Duration) time.Duration {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.failures == nil {
+		b.failures = make(map[types.NamespacedName]int)
+	}
+
+	if maxDelay < base {
+		maxDelay = base
+	}
+
+	delay := base
+	for i := 0; i < b.failures[key] && delay < maxDelay; i++ {
+		delay *= 2
+	}
+
+	b.failures[key]++
+
+	// The jitter is added before the cap, so that the delay never exceeds maxDelay
+	if delay = wait.Jitter(delay, jitterFactor); delay > maxDelay {
+		delay = maxDelay
+	}
+
+	return delay
+}
+
+func (b *failureBackoff) reset(key types.NamespacedName) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	delete(b.failures, key)
+}
+
+// jitterRateLimiter adds jitter to the delays of the wrapped rate limiter,
+// so that objects failing at the same time are not retried simultaneously.
+type jitterRateLimiter struct {
+	workqueue.RateLimiter
+}
+
+func (l jitterRateLimiter) When(item interface{}) time.Duration {
+	return wait.Jitter(l.RateLimiter.When(item), jitterFactor)
+}
@@ -0,0 +1,100 @@
+/*
+Copyright 2025 Edenlab
+*/
+
+package controller
+
+import (
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	internalv1alpha1 "secrets-sync.operators.infra/api/v1alpha1"
+)
+
+func TestFailureBackoff(t *testing.T) {
+	tests := []struct {
+		name     string
+		base     time.Duration
+		maxDelay time.Duration
+		// want are the delays of the consecutive failures before the jitter
+		want []time.Duration
+	}{
+		{
+			name:     "exponential up to the max",
+			base:     time.Second,
+			maxDelay: 5 * time.Second,
+			want:     []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 5 * time.Second, 5 * time.Second},
+		},
+		{
+			name:     "base longer than the max",
+			base:     10 * time.Minute,
+			maxDelay: 5 * time.Minute,
+			want:     []time.Duration{10 * time.Minute, 10 * time.Minute, 10 * time.Minute},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var (
+				b   failureBackoff
+				key = types.NamespacedName{Namespace: "default", Name: "app"}
+			)
+
+			// The jitter is random, the sequence is checked several times
+			for run := 0; run < 20; run++ {
+				for i, want := range tt.want {
+					got := b.next(key, tt.base, tt.maxDelay)
+
+					limit := tt.maxDelay
+					if limit < tt.base {
+						limit = tt.base
+					}
+
+					upper := time.Duration(float64(want) * (1 + jitterFactor))
+					if upper > limit {
+						upper = limit
+					}
+
+					if got < want || got > upper {
+						t.Fatalf("failure %d: next() = %s, want between %s and %s", i, got, want, upper)
+					}
+				}
+
+				b.reset(key)
+			}
+		})
+	}
+}
+
+func TestRefreshInterval(t *testing.T) {
+	tests := []struct {
+		name         string
+		spec         *metav1.Duration
+		managerFlag  time.Duration
+		wantInterval time.Duration
+	}{
+		{name: "default", wantInterval: defaultRefreshInterval},
+		{name: "manager flag", managerFlag: time.Minute, wantInterval: time.Minute},
+		{name: "spec", spec: &metav1.Duration{Duration: 10 * time.Minute}, managerFlag: time.Minute, wantInterval: 10 * time.Minute},
+		{name: "spec below the floor", spec: &metav1.Duration{Duration: time.Millisecond}, wantInterval: minRefreshInterval},
+		{name: "manager flag below the floor", managerFlag: time.Millisecond, wantInterval: minRefreshInterval},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &SecretsSyncReconciler{
+				SystemInfo: &SystemInfo{secretsSync: &internalv1alpha1.SecretsSync{
+					Spec: internalv1alpha1.SecretsSyncSpec{RefreshInterval: tt.spec},
+				}},
+				RefreshInterval: tt.managerFlag,
+			}
+
+			if got := r.refreshInterval(); got != tt.wantInterval {
+				t.Errorf("refreshInterval() = %s, want %s", got, tt.wantInterval)
+			}
+		})
+	}
+}
@@ -0,0 +1,188 @@
+/*
+Copyright 2025 Edenlab
+*/
+
+package controller
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	internalv1alpha1 "secrets-sync.operators.infra/api/v1alpha1"
+	"secrets-sync.operators.infra/internal/keystore"
+)
+
+const (
+	defaultCertificateExpiryWindow = time.Hour * 24 * 30
+
+	conditionCertificateExpiring = "CertificateExpiring"
+	reasonCertificateExpired     = "Expired"
+	reasonCertificateExpiring    = "Expiring"
+	reasonCertificatesValid      = "Valid"
+)
+
+// certificateExpiry exposes notAfter of the synced certificates, the remaining time is calculated by the alert rules
+var certificateExpiry = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+	Name: "secretssync_certificate_expiry_timestamp_seconds",
+	Help: "The notAfter time of the certificates in the synced secrets as a Unix timestamp.",
+}, []string{"secretssync", "namespace", "name", "key"})
+
+func init() {
+	metrics.Registry.MustRegister(certificateExpiry)
+}
+
+// certificateStatuses parses the keys of the secrets holding PEM certificates,
+// only the first certificate of a key is reported as it is the leaf of a chain
+func certificateStatuses(objects ...client.Object) []internalv1alpha1.CertificateStatus {
+	var statuses []internalv1alpha1.CertificateStatus
+
+	for _, obj := range objects {
+		secret, ok := obj.(*v1.Secret)
+		if !ok {
+			continue
+		}
+
+		for key, val := range mergeStringData(secret.Data, secret.StringData) {
+			if !bytes.Contains(val, []byte("-----BEGIN CERTIFICATE-----")) {
+				continue
+			}
+
+			certs, err := keystore.ParseCertificates(val)
+			if err != nil {
+				continue
+			}
+
+			var sans []string
+			sans = append(sans, certs[0].DNSNames...)
+			for _, ip := range certs[0].IPAddresses {
+				sans = append(sans, ip.String())
+			}
+
+			sans = append(sans, certs[0].EmailAddresses...)
+			for _, uri := range certs[0].URIs {
+				sans = append(sans, uri.String())
+			}
+
+			statuses = append(statuses, internalv1alpha1.CertificateStatus{
+				Name:      secret.Name,
+				Namespace: secret.Namespace,
+				Key:       key,
+				NotAfter:  metav1.NewTime(certs[0].NotAfter),
+				Issuer:    certs[0].Issuer.String(),
+				SANs:      sans,
+			})
+		}
+	}
+
+	sort.Slice(statuses, func(i, j int) bool {
+		if statuses[i].Namespace != statuses[j].Namespace {
+			return statuses[i].Namespace < statuses[j].Namespace
+		}
+
+		if statuses[i].Name != statuses[j].Name {
+			return statuses[i].Name < statuses[j].Name
+		}
+
+		return statuses[i].Key < statuses[j].Key
+	})
+
+	return statuses
+}
+
+// updateCertificates records the certificates of the synced secrets in the status and the metrics,
+// a Warning event is emitted when a certificate starts expiring or expires
+func (r *SecretsSyncReconciler) updateCertificates(objects ...client.Object) {
+	statuses := certificateStatuses(objects...)
+
+	r.deleteCertificateMetrics()
+	for _, status := range statuses {
+		certificateExpiry.WithLabelValues(r.req.String(), status.Namespace, status.Name, status.Key).
+			Set(float64(status.NotAfter.Unix()))
+	}
+
+	var (
+		expired  []string
+		expiring []string
+		now      = time.Now()
+		window   = r.certificateExpiryWindow()
+	)
+
+	for _, status := range statuses {
+		name := fmt.Sprintf("%s/%s %s", status.Namespace, status.Name, status.Key)
+		if now.After(status.NotAfter.Time) {
+			expired = append(expired, name)
+		} else if status.NotAfter.Sub(now) < window {
+			expiring = append(expiring, name)
+		}
+	}
+
+	conditions := append([]metav1.Condition(nil), r.secretsSync.Status.Conditions...)
+	condition := metav1.Condition{
+		Type:               conditionCertificateExpiring,
+		Status:             metav1.ConditionFalse,
+		ObservedGeneration: r.secretsSync.Generation,
+		Reason:             reasonCertificatesValid,
+		Message:            fmt.Sprintf("All certificates are valid for more than %s", window),
+	}
+
+	switch {
+	case len(statuses) == 0:
+		meta.RemoveStatusCondition(&conditions, conditionCertificateExpiring)
+	case len(expired) > 0:
+		condition.Status, condition.Reason = metav1.ConditionTrue, reasonCertificateExpired
+		condition.Message = fmt.Sprintf("Certificates expired: %s", strings.Join(expired, ", "))
+		if len(expiring) > 0 {
+			condition.Message += fmt.Sprintf("; expiring within %s: %s", window, strings.Join(expiring, ", "))
+		}
+	case len(expiring) > 0:
+		condition.Status, condition.Reason = metav1.ConditionTrue, reasonCertificateExpiring
+		condition.Message = fmt.Sprintf("Certificates expiring within %s: %s", window, strings.Join(expiring, ", "))
+	}
+
+	previous := meta.FindStatusCondition(r.secretsSync.Status.Conditions, conditionCertificateExpiring)
+	if len(statuses) > 0 {
+		meta.SetStatusCondition(&conditions, condition)
+	}
+
+	conditionChanged := !equality.Semantic.DeepEqual(previous, meta.FindStatusCondition(conditions, conditionCertificateExpiring))
+	if !conditionChanged && equality.Semantic.DeepEqual(r.secretsSync.Status.Certificates, statuses) {
+		return
+	}
+
+	// The event is emitted once per change of the expiring certificates, not on every sync
+	if condition.Status == metav1.ConditionTrue && (previous == nil || previous.Message != condition.Message) {
+		r.reqLogger.Info(condition.Message)
+		r.Recorder.Event(r.secretsSync, v1.EventTypeWarning, condition.Reason, condition.Message)
+	}
+
+	r.secretsSync.Status.Certificates = statuses
+	r.secretsSync.Status.Conditions = conditions
+	r.updateStatusCRD(r.secretsSync.Status.Phase, r.secretsSync.Status.Error, r.secretsSync.Status.Count)
+}
+
+func (r *SecretsSyncReconciler) deleteCertificateMetrics() {
+	certificateExpiry.DeletePartialMatch(prometheus.Labels{"secretssync": r.req.String()})
+}
+
+func (r *SecretsSyncReconciler) certificateExpiryWindow() time.Duration {
+	if r.secretsSync.Spec.CertificateExpiryWindow != nil && r.secretsSync.Spec.CertificateExpiryWindow.Duration > 0 {
+		return r.secretsSync.Spec.CertificateExpiryWindow.Duration
+	}
+
+	if r.CertificateExpiryWindow > 0 {
+		return r.CertificateExpiryWindow
+	}
+
+	return defaultCertificateExpiryWindow
+}
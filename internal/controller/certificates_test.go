@@ -0,0 +1,222 @@
+/*
+Copyright 2025 Edenlab
+*/
+
+package controller
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	internalv1alpha1 "secrets-sync.operators.infra/api/v1alpha1"
+)
+
+// newTestCertificate returns a self-signed PEM certificate of app.default.svc which expires at notAfter
+func newTestCertificate(t *testing.T, notAfter time.Time) []byte {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "app"},
+		NotBefore:    notAfter.AddDate(-1, 0, 0),
+		NotAfter:     notAfter,
+		DNSNames:     []string{"app.default.svc"},
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+}
+
+func TestUpdateCertificates(t *testing.T) {
+	tests := []struct {
+		name       string
+		notAfter   time.Duration
+		wantStatus metav1.ConditionStatus
+		wantReason string
+		wantEvent  bool
+	}{
+		{
+			name:       "expired",
+			notAfter:   -time.Hour,
+			wantStatus: metav1.ConditionTrue,
+			wantReason: reasonCertificateExpired,
+			wantEvent:  true,
+		},
+		{
+			name:       "expiring",
+			notAfter:   24 * time.Hour,
+			wantStatus: metav1.ConditionTrue,
+			wantReason: reasonCertificateExpiring,
+			wantEvent:  true,
+		},
+		{
+			name:       "valid",
+			notAfter:   365 * 24 * time.Hour,
+			wantStatus: metav1.ConditionFalse,
+			wantReason: reasonCertificatesValid,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			scheme := newTestScheme(t)
+			secretsSync := &internalv1alpha1.SecretsSync{ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "default"}}
+			recorder := record.NewFakeRecorder(10)
+
+			r := &SecretsSyncReconciler{
+				SystemInfo: &SystemInfo{
+					ctx:         context.Background(),
+					req:         ctrl.Request{NamespacedName: client.ObjectKeyFromObject(secretsSync)},
+					reqLogger:   logr.Discard(),
+					secretsSync: secretsSync,
+				},
+				Scheme:   scheme,
+				Client:   fake.NewClientBuilder().WithScheme(scheme).WithObjects(secretsSync).Build(),
+				Recorder: recorder,
+			}
+			defer r.deleteCertificateMetrics()
+
+			secret := newTestSecret(func(secret *v1.Secret) {
+				secret.Data[v1.TLSCertKey] = newTestCertificate(t, time.Now().Add(tt.notAfter))
+			})
+
+			// The second sync of the same certificate must not repeat the event
+			for sync := 0; sync < 2; sync++ {
+				r.updateCertificates(secret)
+			}
+
+			certificates := r.secretsSync.Status.Certificates
+			if len(certificates) != 1 || certificates[0].Key != v1.TLSCertKey ||
+				strings.Join(certificates[0].SANs, ",") != "app.default.svc" {
+				t.Errorf("certificates = %v, want the tls.crt of app.default.svc", certificates)
+			}
+
+			condition := meta.FindStatusCondition(r.secretsSync.Status.Conditions, conditionCertificateExpiring)
+			if condition == nil || condition.Status != tt.wantStatus || condition.Reason != tt.wantReason {
+				t.Fatalf("condition = %v, want %s %s", condition, tt.wantStatus, tt.wantReason)
+			}
+
+			wantEvents := 0
+			if tt.wantEvent {
+				wantEvents = 1
+			}
+
+			if len(recorder.Events) != wantEvents {
+				t.Errorf("got %d events, want %d", len(recorder.Events), wantEvents)
+			}
+
+			if tt.wantEvent {
+				if event := <-recorder.Events; !strings.HasPrefix(event, v1.EventTypeWarning+" "+tt.wantReason) {
+					t.Errorf("event = %q, want a Warning %s", event, tt.wantReason)
+				}
+			}
+		})
+	}
+}
+
+func TestUpdateCertificatesRemovesCondition(t *testing.T) {
+	secretsSync := &internalv1alpha1.SecretsSync{
+		ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "default"},
+		Status: internalv1alpha1.SecretsSyncStatus{
+			Certificates: []internalv1alpha1.CertificateStatus{{Name: "app", Namespace: "default", Key: v1.TLSCertKey}},
+			Conditions: []metav1.Condition{{
+				Type: conditionCertificateExpiring, Status: metav1.ConditionTrue, Reason: reasonCertificateExpired,
+			}},
+		},
+	}
+
+	scheme := newTestScheme(t)
+	r := &SecretsSyncReconciler{
+		SystemInfo: &SystemInfo{
+			ctx:         context.Background(),
+			req:         ctrl.Request{NamespacedName: client.ObjectKeyFromObject(secretsSync)},
+			reqLogger:   logr.Discard(),
+			secretsSync: secretsSync,
+		},
+		Scheme:   scheme,
+		Client:   fake.NewClientBuilder().WithScheme(scheme).WithObjects(secretsSync).Build(),
+		Recorder: record.NewFakeRecorder(10),
+	}
+
+	// The secret without certificates replaced the one with the expired certificate
+	r.updateCertificates(newTestSecret(nil))
+
+	if len(r.secretsSync.Status.Certificates) > 0 {
+		t.Errorf("certificates = %v, want none", r.secretsSync.Status.Certificates)
+	}
+
+	if meta.FindStatusCondition(r.secretsSync.Status.Conditions, conditionCertificateExpiring) != nil {
+		t.Error("CertificateExpiring condition is kept without certificates")
+	}
+}
+
+func TestReconcileReportsOnlySyncedCertificates(t *testing.T) {
+	immutable := true
+
+	secretsSync := &internalv1alpha1.SecretsSync{
+		ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "default"},
+		Spec: internalv1alpha1.SecretsSyncSpec{Secrets: map[string]internalv1alpha1.SrcSecret{
+			"app-tls": {SrcNamespace: "platform", DstSecrets: []internalv1alpha1.DstSecret{
+				{Name: "app-tls", Immutable: true, ReplaceStrategy: replaceStrategyKeep},
+			}},
+		}},
+	}
+
+	// The immutable destination differs from the source and is kept by the Keep strategy
+	current := newTestSecret(func(secret *v1.Secret) {
+		secret.Name = "app-tls"
+		secret.Immutable = &immutable
+	})
+
+	r := newTestReconciler(t,
+		&v1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "platform"}},
+		&v1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "app-tls", Namespace: "platform"},
+			Data:       map[string][]byte{v1.TLSCertKey: newTestCertificate(t, time.Now().Add(-time.Hour))},
+			Type:       v1.SecretTypeOpaque,
+		},
+		current,
+		secretsSync,
+	)
+	defer r.deleteCertificateMetrics()
+
+	_, got := reconcileTestSync(t, r, secretsSync)
+	if meta.FindStatusCondition(got.Status.Conditions, conditionImmutableConflict) == nil {
+		t.Fatal("the immutable destination is not reported as a conflict")
+	}
+
+	if len(got.Status.Certificates) > 0 {
+		t.Errorf("certificates = %v, want none of the skipped destination", got.Status.Certificates)
+	}
+
+	if meta.FindStatusCondition(got.Status.Conditions, conditionCertificateExpiring) != nil {
+		t.Error("the expired certificate of the skipped destination raised CertificateExpiring")
+	}
+}
@@ -0,0 +1,152 @@
+/*
+Copyright 2025 Edenlab
+*/
+
+package controller
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+
+	v1 "k8s.io/api/core/v1"
+
+	internalv1alpha1 "secrets-sync.operators.infra/api/v1alpha1"
+)
+
+const (
+	formatDockerConfigJSON = "dockerconfigjson"
+
+	defaultServerKey   = "server"
+	defaultUsernameKey = "username"
+	defaultPasswordKey = "password"
+)
+
+type dockerConfigJSON struct {
+	Auths map[string]dockerConfigEntry `json:"auths"`
+}
+
+type dockerConfigEntry struct {
+	Username string `json:"username,omitempty"`
+	Password string `json:"password,omitempty"`
+	Email    string `json:"email,omitempty"`
+	Auth     string `json:"auth,omitempty"`
+}
+
+// formatData converts the mapped destination keys to the format of the destination,
+// the returned type is empty when the type of the source is kept
+func formatData(dstSecret internalv1alpha1.DstSecret, data map[string][]byte) (map[string][]byte, v1.SecretType, error) {
+	switch dstSecret.Format {
+	case "":
+		return data, "", nil
+	case formatDockerConfigJSON:
+		dockerConfig, err := buildDockerConfigJSON(dstSecret.Registries, data)
+		if err != nil {
+			return nil, "", err
+		}
+
+		return map[string][]byte{v1.DockerConfigJsonKey: dockerConfig}, v1.SecretTypeDockerConfigJson, nil
+	}
+
+	return nil, "", fmt.Errorf("unknown format %s", dstSecret.Format)
+}
+
+// buildDockerConfigJSON assembles a .dockerconfigjson document from the registry credentials keys
+func buildDockerConfigJSON(registries []internalv1alpha1.Registry, data map[string][]byte) ([]byte, error) {
+	dockerConfig := dockerConfigJSON{Auths: make(map[string]dockerConfigEntry)}
+
+	// Without registries the credentials are read from the server, username and password keys
+	if len(registries) == 0 {
+		registries = []internalv1alpha1.Registry{{}}
+	}
+
+	for _, registry := range registries {
+		server := registry.Server
+		if len(server) == 0 {
+			val, err := requiredKey(data, registry.ServerKey, defaultServerKey)
+			if err != nil {
+				return nil, err
+			}
+
+			server = string(val)
+		}
+
+		username, err := requiredKey(data, registry.UsernameKey, defaultUsernameKey)
+		if err != nil {
+			return nil, err
+		}
+
+		password, err := requiredKey(data, registry.PasswordKey, defaultPasswordKey)
+		if err != nil {
+			return nil, err
+		}
+
+		entry := dockerConfigEntry{
+			Username: string(username),
+			Password: string(password),
+			Auth:     base64.StdEncoding.EncodeToString([]byte(string(username) + ":" + string(password))),
+		}
+
+		if len(registry.EmailKey) > 0 {
+			entry.Email = string(data[registry.EmailKey])
+		}
+
+		// The registries are keyed by the server, a duplicate would silently replace the earlier credentials
+		if _, ok := dockerConfig.Auths[server]; ok {
+			return nil, fmt.Errorf("registry %s is listed more than once", server)
+		}
+
+		dockerConfig.Auths[server] = entry
+	}
+
+	return json.Marshal(dockerConfig)
+}
+
+// validateSecretType checks that the data contains the keys required by the secret type
+func validateSecretType(secretType v1.SecretType, data map[string][]byte) error {
+	var requiredKeys []string
+
+	switch secretType {
+	case v1.SecretTypeTLS:
+		requiredKeys = []string{v1.TLSCertKey, v1.TLSPrivateKeyKey}
+	case v1.SecretTypeSSHAuth:
+		requiredKeys = []string{v1.SSHAuthPrivateKey}
+	case v1.SecretTypeDockercfg:
+		requiredKeys = []string{v1.DockerConfigKey}
+	case v1.SecretTypeDockerConfigJson:
+		requiredKeys = []string{v1.DockerConfigJsonKey}
+	case v1.SecretTypeBasicAuth:
+		_, hasUsername := data[v1.BasicAuthUsernameKey]
+		_, hasPassword := data[v1.BasicAuthPasswordKey]
+		if !hasUsername && !hasPassword {
+			return fmt.Errorf("type %s requires key %s or %s", secretType, v1.BasicAuthUsernameKey, v1.BasicAuthPasswordKey)
+		}
+	case v1.SecretTypeServiceAccountToken:
+		return fmt.Errorf("type %s is managed by the token controller and can not be synced", secretType)
+	}
+
+	for _, key := range requiredKeys {
+		if _, ok := data[key]; !ok {
+			return fmt.Errorf("type %s requires key %s", secretType, key)
+		}
+	}
+
+	if val, ok := data[v1.DockerConfigJsonKey]; ok && secretType == v1.SecretTypeDockerConfigJson && !json.Valid(val) {
+		return fmt.Errorf("type %s requires valid JSON in key %s", secretType, v1.DockerConfigJsonKey)
+	}
+
+	return nil
+}
+
+func requiredKey(data map[string][]byte, key, defaultKey string) ([]byte, error) {
+	if len(key) == 0 {
+		key = defaultKey
+	}
+
+	val, ok := data[key]
+	if !ok {
+		return nil, fmt.Errorf("key %s not found", key)
+	}
+
+	return val, nil
+}
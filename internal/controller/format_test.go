@@ -0,0 +1,184 @@
+/*
+Copyright 2025 Edenlab
+*/
+
+package controller
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+
+	internalv1alpha1 "secrets-sync.operators.infra/api/v1alpha1"
+)
+
+func TestBuildDockerConfigJSON(t *testing.T) {
+	data := map[string][]byte{
+		"server":         []byte("registry.example.com"),
+		"username":       []byte("robot"),
+		"password":       []byte("s3cr:t"),
+		"mirror":         []byte("mirror.example.com"),
+		"mirror-user":    []byte("mirror"),
+		"mirror-token":   []byte("token"),
+		"mirror-email":   []byte("robot@example.com"),
+		"other-server":   []byte("registry.example.com"),
+		"other-user":     []byte("other"),
+		"other-password": []byte("other"),
+	}
+
+	tests := []struct {
+		name       string
+		registries []internalv1alpha1.Registry
+		want       map[string]dockerConfigEntry
+		wantErr    string
+	}{
+		{
+			name: "default keys",
+			want: map[string]dockerConfigEntry{
+				// The auth is the base64 of username:password, the password may contain a colon
+				"registry.example.com": {Username: "robot", Password: "s3cr:t", Auth: "cm9ib3Q6czNjcjp0"},
+			},
+		},
+		{
+			name: "multiple registries",
+			registries: []internalv1alpha1.Registry{
+				{},
+				{ServerKey: "mirror", UsernameKey: "mirror-user", PasswordKey: "mirror-token", EmailKey: "mirror-email"},
+			},
+			want: map[string]dockerConfigEntry{
+				"registry.example.com": {Username: "robot", Password: "s3cr:t", Auth: "cm9ib3Q6czNjcjp0"},
+				"mirror.example.com": {
+					Username: "mirror", Password: "token", Email: "robot@example.com", Auth: "bWlycm9yOnRva2Vu",
+				},
+			},
+		},
+		{
+			name:       "server wins over server key",
+			registries: []internalv1alpha1.Registry{{Server: "ghcr.io", ServerKey: "mirror"}},
+			want: map[string]dockerConfigEntry{
+				"ghcr.io": {Username: "robot", Password: "s3cr:t", Auth: "cm9ib3Q6czNjcjp0"},
+			},
+		},
+		{
+			name:       "missing server key",
+			registries: []internalv1alpha1.Registry{{ServerKey: "registry"}},
+			wantErr:    "key registry not found",
+		},
+		{
+			name:       "missing password key",
+			registries: []internalv1alpha1.Registry{{Server: "ghcr.io", PasswordKey: "token"}},
+			wantErr:    "key token not found",
+		},
+		{
+			name: "duplicate server",
+			registries: []internalv1alpha1.Registry{
+				{},
+				{ServerKey: "other-server", UsernameKey: "other-user", PasswordKey: "other-password"},
+			},
+			wantErr: "registry registry.example.com is listed more than once",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := buildDockerConfigJSON(tt.registries, data)
+			if len(tt.wantErr) > 0 {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("buildDockerConfigJSON() error = %v, want %q", err, tt.wantErr)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("buildDockerConfigJSON() error = %v", err)
+			}
+
+			var dockerConfig dockerConfigJSON
+			if err := json.Unmarshal(got, &dockerConfig); err != nil {
+				t.Fatal(err)
+			}
+
+			if !reflect.DeepEqual(dockerConfig.Auths, tt.want) {
+				t.Errorf("buildDockerConfigJSON() = %v, want %v", dockerConfig.Auths, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateSecretType(t *testing.T) {
+	tests := []struct {
+		name       string
+		secretType v1.SecretType
+		keys       []string
+		dockerJSON string
+		wantErr    string
+	}{
+		{name: "opaque without keys", secretType: v1.SecretTypeOpaque},
+		{name: "tls", secretType: v1.SecretTypeTLS, keys: []string{v1.TLSCertKey, v1.TLSPrivateKeyKey}},
+		{
+			name:       "tls without key",
+			secretType: v1.SecretTypeTLS,
+			keys:       []string{v1.TLSCertKey},
+			wantErr:    "requires key tls.key",
+		},
+		{name: "basic-auth with username", secretType: v1.SecretTypeBasicAuth, keys: []string{v1.BasicAuthUsernameKey}},
+		{name: "basic-auth with password", secretType: v1.SecretTypeBasicAuth, keys: []string{v1.BasicAuthPasswordKey}},
+		{
+			name:       "basic-auth without credentials",
+			secretType: v1.SecretTypeBasicAuth,
+			keys:       []string{"token"},
+			wantErr:    "requires key username or password",
+		},
+		{name: "ssh-auth", secretType: v1.SecretTypeSSHAuth, keys: []string{v1.SSHAuthPrivateKey}},
+		{name: "ssh-auth without key", secretType: v1.SecretTypeSSHAuth, wantErr: "requires key ssh-privatekey"},
+		{name: "dockercfg without key", secretType: v1.SecretTypeDockercfg, wantErr: "requires key .dockercfg"},
+		{name: "dockerconfigjson", secretType: v1.SecretTypeDockerConfigJson, dockerJSON: `{"auths":{}}`},
+		{
+			name:       "dockerconfigjson without key",
+			secretType: v1.SecretTypeDockerConfigJson,
+			wantErr:    "requires key .dockerconfigjson",
+		},
+		{
+			name:       "dockerconfigjson with invalid JSON",
+			secretType: v1.SecretTypeDockerConfigJson,
+			dockerJSON: `{"auths":`,
+			wantErr:    "requires valid JSON",
+		},
+		{
+			name:       "service-account-token",
+			secretType: v1.SecretTypeServiceAccountToken,
+			keys:       []string{v1.ServiceAccountTokenKey},
+			wantErr:    "managed by the token controller",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data := make(map[string][]byte)
+			for _, key := range tt.keys {
+				data[key] = []byte("value")
+			}
+
+			if len(tt.dockerJSON) > 0 {
+				data[v1.DockerConfigJsonKey] = []byte(tt.dockerJSON)
+			}
+
+			err := validateSecretType(tt.secretType, data)
+			if len(tt.wantErr) == 0 {
+				if err != nil {
+					t.Errorf("validateSecretType() error = %v", err)
+				}
+
+				return
+			}
+
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("validateSecretType() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,169 @@
+/*
+Copyright 2025 Edenlab
+*/
+
+package controller
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	imagePullServiceAccountsAnnotation = "internal.edenlab.io/image-pull-service-accounts"
+	// imagePullSecretsAnnotation lists the image pull secrets of a service account added by the operator,
+	// only these references are removed
+	imagePullSecretsAnnotation = "internal.edenlab.io/image-pull-secrets"
+)
+
+// hasImagePullServiceAccounts reports whether the current secret in the cluster has
+// the image-pull-service-accounts annotation, it is read before the secret is synced
+func hasImagePullServiceAccounts(ctx context.Context, c client.Reader, obj client.Object) (bool, error) {
+	if _, ok := obj.(*v1.Secret); !ok {
+		return false, nil
+	}
+
+	current := &v1.Secret{}
+	if err := c.Get(ctx, client.ObjectKeyFromObject(obj), current); err != nil {
+		if errors.IsNotFound(err) {
+			return false, nil
+		}
+
+		return false, err
+	}
+
+	_, ok := current.Annotations[imagePullServiceAccountsAnnotation]
+
+	return ok, nil
+}
+
+// syncImagePullSecret adds the secret to imagePullSecrets of the service accounts in its namespace matching
+// the image-pull-service-accounts annotation of the secret and removes it from the others it was added to.
+// The references added by other tools are left untouched. The service accounts are only changed
+// when the secret has the annotation or had it before the sync.
+func syncImagePullSecret(ctx context.Context, c client.Client, obj client.Object, hadAnnotation bool) error {
+	value, ok := obj.GetAnnotations()[imagePullServiceAccountsAnnotation]
+	if _, isSecret := obj.(*v1.Secret); !isSecret || (!ok && !hadAnnotation) {
+		return nil
+	}
+
+	patterns := splitList(value)
+
+	listServiceAccounts := &v1.ServiceAccountList{}
+	if err := c.List(ctx, listServiceAccounts, client.InNamespace(obj.GetNamespace())); err != nil {
+		return err
+	}
+
+	for i := range listServiceAccounts.Items {
+		serviceAccount := &listServiceAccounts.Items[i]
+
+		matched := matchPatterns(patterns, serviceAccount.Name)
+		add := matched && !hasImagePullSecret(serviceAccount, obj.GetName())
+		remove := !matched && addedImagePullSecret(serviceAccount, obj.GetName())
+		if !add && !remove {
+			continue
+		}
+
+		if err := setImagePullSecret(ctx, c, serviceAccount, obj.GetName(), matched); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// removeImagePullSecret removes the garbage-collected secret from imagePullSecrets
+// of the service accounts in its namespace it was added to
+func removeImagePullSecret(ctx context.Context, c client.Client, obj client.Object) error {
+	if _, ok := obj.GetAnnotations()[imagePullServiceAccountsAnnotation]; !ok {
+		return nil
+	}
+
+	listServiceAccounts := &v1.ServiceAccountList{}
+	if err := c.List(ctx, listServiceAccounts, client.InNamespace(obj.GetNamespace())); err != nil {
+		return err
+	}
+
+	for i := range listServiceAccounts.Items {
+		if !addedImagePullSecret(&listServiceAccounts.Items[i], obj.GetName()) {
+			continue
+		}
+
+		if err := setImagePullSecret(ctx, c, &listServiceAccounts.Items[i], obj.GetName(), false); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// setImagePullSecret adds the secret to imagePullSecrets of the service account or removes it
+// and records the change in the image-pull-secrets annotation
+func setImagePullSecret(ctx context.Context, c client.Client, serviceAccount *v1.ServiceAccount, name string, add bool) error {
+	patch := client.MergeFromWithOptions(serviceAccount.DeepCopy(), client.MergeFromWithOptimisticLock{})
+
+	var added []string
+	for _, item := range splitList(serviceAccount.Annotations[imagePullSecretsAnnotation]) {
+		if item != name {
+			added = append(added, item)
+		}
+	}
+
+	if add {
+		added = append(added, name)
+	}
+
+	if len(added) > 0 {
+		sort.Strings(added)
+		if serviceAccount.Annotations == nil {
+			serviceAccount.Annotations = make(map[string]string)
+		}
+
+		serviceAccount.Annotations[imagePullSecretsAnnotation] = strings.Join(added, ",")
+	} else {
+		delete(serviceAccount.Annotations, imagePullSecretsAnnotation)
+	}
+
+	if add {
+		if !hasImagePullSecret(serviceAccount, name) {
+			serviceAccount.ImagePullSecrets = append(serviceAccount.ImagePullSecrets, v1.LocalObjectReference{Name: name})
+		}
+	} else {
+		var imagePullSecrets []v1.LocalObjectReference
+		for _, ref := range serviceAccount.ImagePullSecrets {
+			if ref.Name != name {
+				imagePullSecrets = append(imagePullSecrets, ref)
+			}
+		}
+
+		serviceAccount.ImagePullSecrets = imagePullSecrets
+	}
+
+	return c.Patch(ctx, serviceAccount, patch)
+}
+
+func hasImagePullSecret(serviceAccount *v1.ServiceAccount, name string) bool {
+	for _, ref := range serviceAccount.ImagePullSecrets {
+		if ref.Name == name {
+			return true
+		}
+	}
+
+	return false
+}
+
+// addedImagePullSecret reports whether the operator added the secret to imagePullSecrets of the service account
+func addedImagePullSecret(serviceAccount *v1.ServiceAccount, name string) bool {
+	for _, item := range splitList(serviceAccount.Annotations[imagePullSecretsAnnotation]) {
+		if item == name {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,170 @@
+/*
+Copyright 2025 Edenlab
+*/
+
+package controller
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+// newTestServiceAccounts returns the service accounts of the image pull tests, the app reference
+// of default was added by the operator and the one of runner by another tool
+func newTestServiceAccounts() []client.Object {
+	serviceAccount := func(name, added string, imagePullSecrets ...string) *v1.ServiceAccount {
+		sa := &v1.ServiceAccount{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"}}
+		if len(added) > 0 {
+			sa.Annotations = map[string]string{imagePullSecretsAnnotation: added}
+		}
+
+		for _, secret := range imagePullSecrets {
+			sa.ImagePullSecrets = append(sa.ImagePullSecrets, v1.LocalObjectReference{Name: secret})
+		}
+
+		return sa
+	}
+
+	return []client.Object{
+		serviceAccount("default", "app", "app"),
+		serviceAccount("builder-1", ""),
+		serviceAccount("deployer", "", "other"),
+		serviceAccount("runner", "", "other", "app"),
+	}
+}
+
+// checkImagePullSecrets compares the references and the image-pull-secrets annotations of the service accounts
+func checkImagePullSecrets(t *testing.T, c client.Client, want map[string][]string, wantAdded map[string]string) {
+	t.Helper()
+
+	for name, wantRefs := range want {
+		sa := &v1.ServiceAccount{}
+		if err := c.Get(context.Background(), client.ObjectKey{Namespace: "default", Name: name}, sa); err != nil {
+			t.Fatal(err)
+		}
+
+		var got []string
+		for _, ref := range sa.ImagePullSecrets {
+			got = append(got, ref.Name)
+		}
+
+		if !reflect.DeepEqual(got, wantRefs) {
+			t.Errorf("service account %s has %v, want %v", name, got, wantRefs)
+		}
+
+		if added := sa.Annotations[imagePullSecretsAnnotation]; added != wantAdded[name] {
+			t.Errorf("service account %s has the added secrets %q, want %q", name, added, wantAdded[name])
+		}
+	}
+}
+
+func TestSyncImagePullSecret(t *testing.T) {
+	tests := []struct {
+		name          string
+		annotation    *string
+		hadAnnotation bool
+		want          map[string][]string
+		wantAdded     map[string]string
+	}{
+		{
+			name:       "matching service accounts are added",
+			annotation: stringPtr("builder-*, default, runner"),
+			want: map[string][]string{
+				"default":   {"app"},
+				"builder-1": {"app"},
+				"deployer":  {"other"},
+				"runner":    {"other", "app"},
+			},
+			wantAdded: map[string]string{"default": "app", "builder-1": "app"},
+		},
+		{
+			name:          "narrowed patterns remove the added references",
+			annotation:    stringPtr("builder-*"),
+			hadAnnotation: true,
+			want: map[string][]string{
+				"default":   nil,
+				"builder-1": {"app"},
+				"deployer":  {"other"},
+				"runner":    {"other", "app"},
+			},
+			wantAdded: map[string]string{"builder-1": "app"},
+		},
+		{
+			name:          "removed annotation removes only the added references",
+			hadAnnotation: true,
+			want: map[string][]string{
+				"default":   nil,
+				"builder-1": nil,
+				"deployer":  {"other"},
+				"runner":    {"other", "app"},
+			},
+		},
+		{
+			name: "never managed secret leaves the service accounts untouched",
+			want: map[string][]string{
+				"default":   {"app"},
+				"builder-1": nil,
+				"deployer":  {"other"},
+				"runner":    {"other", "app"},
+			},
+			wantAdded: map[string]string{"default": "app"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := fake.NewClientBuilder().WithObjects(newTestServiceAccounts()...).Build()
+
+			secret := newTestSecret(nil)
+			if tt.annotation != nil {
+				secret.Annotations[imagePullServiceAccountsAnnotation] = *tt.annotation
+			}
+
+			if err := syncImagePullSecret(context.Background(), c, secret, tt.hadAnnotation); err != nil {
+				t.Fatalf("syncImagePullSecret() error = %v", err)
+			}
+
+			checkImagePullSecrets(t, c, tt.want, tt.wantAdded)
+		})
+	}
+}
+
+func TestRemoveImagePullSecret(t *testing.T) {
+	c := fake.NewClientBuilder().WithObjects(newTestServiceAccounts()...).Build()
+
+	secret := newTestSecret(func(secret *v1.Secret) {
+		secret.Annotations[imagePullServiceAccountsAnnotation] = "*"
+	})
+
+	if err := removeImagePullSecret(context.Background(), c, secret); err != nil {
+		t.Fatalf("removeImagePullSecret() error = %v", err)
+	}
+
+	checkImagePullSecrets(t, c, map[string][]string{
+		"default":   nil,
+		"builder-1": nil,
+		"deployer":  {"other"},
+		"runner":    {"other", "app"},
+	}, nil)
+}
+
+func TestImagePullAnnotationIsManaged(t *testing.T) {
+	current := newTestSecret(func(secret *v1.Secret) {
+		secret.Annotations[imagePullServiceAccountsAnnotation] = "default"
+		secret.Annotations[managedAnnotationsAnnotation] = addMetadataKey("", imagePullServiceAccountsAnnotation)
+	})
+
+	if got := objectChange(current, newTestSecret(nil)); got != planActionUpdate {
+		t.Errorf("objectChange() = %q after the annotation was removed, want %q", got, planActionUpdate)
+	}
+}
+
+func stringPtr(s string) *string {
+	return &s
+}
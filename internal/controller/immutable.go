@@ -0,0 +1,111 @@
+/*
+Copyright 2025 Edenlab
+*/
+
+package controller
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	internalv1alpha1 "secrets-sync.operators.infra/api/v1alpha1"
+)
+
+const (
+	replaceStrategyKeep = "Keep"
+
+	// replaceStrategyAnnotation marks the immutable destinations which are never recreated
+	replaceStrategyAnnotation = "internal.edenlab.io/replace-strategy"
+
+	conditionImmutableConflict = "ImmutableConflict"
+	reasonImmutableDestination = "ImmutableDestination"
+	reasonNoConflicts          = "NoConflicts"
+)
+
+var errImmutable = fmt.Errorf("object is immutable and can not be replaced")
+
+// markImmutable makes the destination immutable and records the Keep replace strategy on it
+func markImmutable(obj client.Object, dstSecret internalv1alpha1.DstSecret) {
+	if !dstSecret.Immutable {
+		return
+	}
+
+	immutable := true
+	switch o := obj.(type) {
+	case *v1.Secret:
+		o.Immutable = &immutable
+	case *v1.ConfigMap:
+		o.Immutable = &immutable
+	}
+
+	if dstSecret.ReplaceStrategy != replaceStrategyKeep {
+		return
+	}
+
+	annotations := obj.GetAnnotations()
+	if annotations == nil {
+		annotations = make(map[string]string)
+	}
+
+	annotations[replaceStrategyAnnotation] = replaceStrategyKeep
+	obj.SetAnnotations(annotations)
+}
+
+// checkImmutable returns errImmutable when the immutable current object has to be recreated
+// and the desired one is kept by the Keep replace strategy
+func checkImmutable(current, desired client.Object, action string) error {
+	if action == planActionRecreate && isImmutable(current) &&
+		desired.GetAnnotations()[replaceStrategyAnnotation] == replaceStrategyKeep {
+		return errImmutable
+	}
+
+	return nil
+}
+
+// updateImmutableCondition reports the immutable objects which differ from their destinations and are not replaced,
+// the condition is only kept for the SecretsSyncs with immutable or merged destinations
+func (r *SecretsSyncReconciler) updateImmutableCondition(conflicts []string) {
+	conditions := append([]metav1.Condition(nil), r.secretsSync.Status.Conditions...)
+
+	switch {
+	case len(conflicts) > 0:
+		sort.Strings(conflicts)
+		meta.SetStatusCondition(&conditions, metav1.Condition{
+			Type:               conditionImmutableConflict,
+			Status:             metav1.ConditionTrue,
+			ObservedGeneration: r.secretsSync.Generation,
+			Reason:             reasonImmutableDestination,
+			Message:            fmt.Sprintf("Immutable objects differ from the destinations: %s", strings.Join(conflicts, ", ")),
+		})
+	case r.hasImmutable():
+		meta.SetStatusCondition(&conditions, metav1.Condition{
+			Type:               conditionImmutableConflict,
+			Status:             metav1.ConditionFalse,
+			ObservedGeneration: r.secretsSync.Generation,
+			Reason:             reasonNoConflicts,
+			Message:            "All immutable destinations are in sync",
+		})
+	default:
+		meta.RemoveStatusCondition(&conditions, conditionImmutableConflict)
+	}
+
+	r.saveConditions(conditions)
+}
+
+func (r *SecretsSyncReconciler) hasImmutable() bool {
+	for _, val := range r.secretsSync.Spec.Secrets {
+		for _, dstSecret := range val.DstSecrets {
+			if dstSecret.Immutable || dstSecret.Mode == modeMerge {
+				return true
+			}
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,322 @@
+/*
+Copyright 2025 Edenlab
+*/
+
+package controller
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/client-go/util/jsonpath"
+	"sigs.k8s.io/yaml"
+
+	internalv1alpha1 "secrets-sync.operators.infra/api/v1alpha1"
+	"secrets-sync.operators.infra/internal/expression"
+)
+
+// mapKeys renames the source keys of the destination, flattens the structured keys into dotted keys,
+// copies the keys of the key mappings and extracts the fields of the key:$.json.path mappings
+func mapKeys(dstSecret internalv1alpha1.DstSecret, srcSecret *v1.Secret) (map[string][]byte, map[string]string, error) {
+	data := make(map[string][]byte)
+	stringData := make(map[string]string)
+
+	// The flattened keys, the keys of the mappings and the extracted documents are not copied as is
+	skip := make(map[string]bool, len(dstSecret.Flatten)+len(dstSecret.KeyMappings)+len(dstSecret.Keys))
+	for _, key := range dstSecret.Flatten {
+		skip[key] = true
+	}
+
+	for _, mapping := range dstSecret.KeyMappings {
+		if !mapping.KeepOriginal {
+			skip[mapping.From] = true
+		}
+	}
+
+	// The documents of the extracted fields are only copied when they are renamed as well
+	for mapping := range dstSecret.Keys {
+		if key, _, ok := strings.Cut(mapping, ":"); ok {
+			if _, renamed := dstSecret.Keys[key]; !renamed {
+				skip[key] = true
+			}
+		}
+	}
+
+	for key, val := range srcSecret.Data {
+		if !skip[key] {
+			data[renameKey(dstSecret, key)] = val
+		}
+	}
+
+	for key, val := range srcSecret.StringData {
+		if !skip[key] {
+			stringData[renameKey(dstSecret, key)] = val
+		}
+	}
+
+	for _, key := range dstSecret.Flatten {
+		doc, err := structuredValue(srcSecret, key)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		fields := make(map[string][]byte)
+		if err := flattenValue("", doc, fields); err != nil {
+			return nil, nil, fmt.Errorf("key %s: %w", key, err)
+		}
+
+		for field, val := range fields {
+			name := renameKey(dstSecret, field)
+			if errs := validation.IsConfigMapKey(name); len(errs) > 0 {
+				return nil, nil, fmt.Errorf("key %s: invalid flattened key %s: %s", key, name, strings.Join(errs, ", "))
+			}
+
+			if _, ok := data[name]; ok {
+				return nil, nil, fmt.Errorf("key %s: flattened key %s conflicts with an existing key", key, name)
+			}
+
+			data[name] = val
+		}
+	}
+
+	for _, mapping := range dstSecret.KeyMappings {
+		val, ok := srcSecret.Data[mapping.From]
+		if stringVal, isString := srcSecret.StringData[mapping.From]; isString {
+			val, ok = []byte(stringVal), true
+		}
+
+		if !ok {
+			// Missing keys without a default are skipped like the missing keys of the renames
+			if mapping.Default == nil {
+				continue
+			}
+
+			val = []byte(*mapping.Default)
+		}
+
+		// The copies never replace the renamed keys or the copies of other mappings
+		for _, name := range mapping.To {
+			_, inData := data[name]
+			_, inStringData := stringData[name]
+			if inData || inStringData {
+				return nil, nil, fmt.Errorf("key mapping %s: key %s conflicts with an existing key", mapping.From, name)
+			}
+
+			data[name] = val
+		}
+	}
+
+	// The mappings are extracted in a stable order, so that a conflict is always reported for the same mapping
+	mappings := make([]string, 0, len(dstSecret.Keys))
+	for mapping := range dstSecret.Keys {
+		mappings = append(mappings, mapping)
+	}
+
+	sort.Strings(mappings)
+
+	for _, mapping := range mappings {
+		key, path, ok := strings.Cut(mapping, ":")
+		if !ok {
+			continue
+		}
+
+		name := dstSecret.Keys[mapping]
+		if errs := validation.IsConfigMapKey(name); len(errs) > 0 {
+			return nil, nil, fmt.Errorf("key %s: invalid key %s: %s", mapping, name, strings.Join(errs, ", "))
+		}
+
+		// The extracted fields never replace the copied keys or the fields of other mappings
+		_, inData := data[name]
+		_, inStringData := stringData[name]
+		if inData || inStringData {
+			return nil, nil, fmt.Errorf("key %s: key %s conflicts with an existing key", mapping, name)
+		}
+
+		doc, err := structuredValue(srcSecret, key)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		val, err := extractField(doc, path)
+		if err != nil {
+			return nil, nil, fmt.Errorf("key %s: %w", mapping, err)
+		}
+
+		data[name] = val
+	}
+
+	return data, stringData, nil
+}
+
+// computeKeys stores the values of the CEL expressions of the computed keys
+func computeKeys(dstSecret internalv1alpha1.DstSecret, srcSecret *v1.Secret, data map[string][]byte) error {
+	if len(dstSecret.ComputedKeys) == 0 {
+		return nil
+	}
+
+	src := expressionSource(srcSecret)
+	for key, expr := range dstSecret.ComputedKeys {
+		val, err := expression.EvalValue(expr, src)
+		if err != nil {
+			return fmt.Errorf("computed key %s: %w", key, err)
+		}
+
+		data[key] = val
+	}
+
+	return nil
+}
+
+// expressionSource exposes the source to the CEL expressions, the string data is folded into the data
+func expressionSource(srcSecret *v1.Secret) expression.Source {
+	return expression.Source{
+		Name:        srcSecret.Name,
+		Namespace:   srcSecret.Namespace,
+		Type:        string(srcSecret.Type),
+		Labels:      srcSecret.Labels,
+		Annotations: srcSecret.Annotations,
+		Data:        mergeStringData(srcSecret.Data, srcSecret.StringData),
+	}
+}
+
+// missingKeysError reports the required keys missing in a destination
+type missingKeysError struct {
+	keys []string
+}
+
+func (e *missingKeysError) Error() string {
+	return fmt.Sprintf("missing required keys %s", strings.Join(e.keys, ", "))
+}
+
+func checkRequiredKeys(dstSecret internalv1alpha1.DstSecret, data map[string][]byte) error {
+	var missing []string
+
+	for _, key := range dstSecret.RequiredKeys {
+		if _, ok := data[key]; !ok {
+			missing = append(missing, key)
+		}
+	}
+
+	if len(missing) > 0 {
+		return &missingKeysError{keys: missing}
+	}
+
+	return nil
+}
+
+func renameKey(dstSecret internalv1alpha1.DstSecret, key string) string {
+	if keyName, ok := dstSecret.Keys[key]; ok {
+		return keyName
+	}
+
+	return key
+}
+
+// structuredValue parses the JSON or YAML document stored in the source key
+func structuredValue(srcSecret *v1.Secret, key string) (interface{}, error) {
+	val, ok := srcSecret.Data[key]
+	if !ok {
+		stringVal, ok := srcSecret.StringData[key]
+		if !ok {
+			return nil, fmt.Errorf("key %s not found", key)
+		}
+
+		val = []byte(stringVal)
+	}
+
+	// YAML is a superset of JSON, the documents are converted to the JSON types
+	var doc interface{}
+	if err := yaml.Unmarshal(val, &doc); err != nil {
+		return nil, fmt.Errorf("key %s is neither JSON nor YAML: %w", key, err)
+	}
+
+	return doc, nil
+}
+
+// extractField evaluates the JSONPath expression, e.g. $.database.password, which has to match a single value.
+// Strings are returned as is, other values are encoded as JSON.
+func extractField(doc interface{}, path string) ([]byte, error) {
+	parser := jsonpath.New("key")
+	if err := parser.Parse("{" + path + "}"); err != nil {
+		return nil, err
+	}
+
+	results, err := parser.FindResults(doc)
+	if err != nil {
+		return nil, err
+	}
+
+	var values []interface{}
+	for _, result := range results {
+		for _, val := range result {
+			values = append(values, val.Interface())
+		}
+	}
+
+	if len(values) != 1 {
+		return nil, fmt.Errorf("path %s matches %d values, expected one", path, len(values))
+	}
+
+	return scalarValue(values[0])
+}
+
+// flattenValue stores the leaves of the document under the dotted paths, the list items by their index
+func flattenValue(prefix string, val interface{}, fields map[string][]byte) error {
+	join := func(key string) string {
+		if len(prefix) == 0 {
+			return key
+		}
+
+		return prefix + "." + key
+	}
+
+	switch typed := val.(type) {
+	case map[string]interface{}:
+		for key, item := range typed {
+			if err := flattenValue(join(key), item, fields); err != nil {
+				return err
+			}
+		}
+	case []interface{}:
+		for i, item := range typed {
+			if err := flattenValue(join(strconv.Itoa(i)), item, fields); err != nil {
+				return err
+			}
+		}
+	default:
+		if len(prefix) == 0 {
+			return fmt.Errorf("expected an object or a list")
+		}
+
+		encoded, err := scalarValue(typed)
+		if err != nil {
+			return err
+		}
+
+		fields[prefix] = encoded
+	}
+
+	return nil
+}
+
+func scalarValue(val interface{}) ([]byte, error) {
+	switch typed := val.(type) {
+	case string:
+		return []byte(typed), nil
+	case nil:
+		return []byte{}, nil
+	}
+
+	encoded, err := json.Marshal(val)
+	if err != nil {
+		return nil, err
+	}
+
+	return bytes.TrimSpace(encoded), nil
+}
@@ -0,0 +1,310 @@
+/*
+Copyright 2025 Edenlab
+*/
+
+package controller
+
+import (
+	"strings"
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/yaml"
+
+	internalv1alpha1 "secrets-sync.operators.infra/api/v1alpha1"
+)
+
+const testConfig = `{"database": {"host": "db", "port": 5432, "password": "secret", "replicas": ["a", "b"],
+"tls": true, "options": null}}`
+
+func parseTestDoc(t *testing.T, doc string) interface{} {
+	t.Helper()
+
+	var parsed interface{}
+	if err := yaml.Unmarshal([]byte(doc), &parsed); err != nil {
+		t.Fatal(err)
+	}
+
+	return parsed
+}
+
+func TestExtractField(t *testing.T) {
+	doc := parseTestDoc(t, testConfig)
+
+	tests := []struct {
+		path    string
+		want    string
+		wantErr bool
+	}{
+		{path: "$.database.password", want: "secret"},
+		{path: ".database.host", want: "db"},
+		{path: "$.database.port", want: "5432"},
+		{path: "$.database.tls", want: "true"},
+		{path: "$.database.options", want: ""},
+		{path: "$.database.replicas[1]", want: "b"},
+		{path: "$.database.replicas", want: `["a","b"]`},
+		{path: "$.database", want: `{"host":"db","options":null,"password":"secret","port":5432,"replicas":["a","b"],"tls":true}`},
+		{path: "$.database.replicas[*]", wantErr: true},
+		{path: "$.database.user", wantErr: true},
+		{path: "$.database[", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			got, err := extractField(doc, tt.path)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("extractField() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if string(got) != tt.want {
+				t.Errorf("extractField() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFlattenValue(t *testing.T) {
+	fields := make(map[string][]byte)
+	if err := flattenValue("", parseTestDoc(t, testConfig), fields); err != nil {
+		t.Fatalf("flattenValue() error = %v", err)
+	}
+
+	want := map[string]string{
+		"database.host":       "db",
+		"database.port":       "5432",
+		"database.password":   "secret",
+		"database.replicas.0": "a",
+		"database.replicas.1": "b",
+		"database.tls":        "true",
+		"database.options":    "",
+	}
+
+	if len(fields) != len(want) {
+		t.Errorf("flattenValue() = %d fields, want %d", len(fields), len(want))
+	}
+
+	for key, val := range want {
+		if got, ok := fields[key]; !ok || string(got) != val {
+			t.Errorf("field %s = %q, want %q", key, got, val)
+		}
+	}
+
+	if err := flattenValue("", "scalar", map[string][]byte{}); err == nil {
+		t.Error("flattenValue() of a scalar document error = nil, want an error")
+	}
+}
+
+func TestMapKeysExtraction(t *testing.T) {
+	srcSecret := &v1.Secret{
+		Data: map[string][]byte{
+			"config.json": []byte(testConfig),
+			"token":       []byte("abc"),
+		},
+		StringData: map[string]string{"region": "eu"},
+	}
+
+	tests := []struct {
+		name    string
+		keys    map[string]string
+		want    map[string]string
+		wantErr string
+	}{
+		{
+			name: "extracted document is not copied",
+			keys: map[string]string{"config.json:$.database.password": "DB_PASSWORD"},
+			want: map[string]string{"DB_PASSWORD": "secret", "token": "abc"},
+		},
+		{
+			name: "extracted document renamed as well",
+			keys: map[string]string{"config.json:$.database.host": "DB_HOST", "config.json": "CONFIG"},
+			want: map[string]string{"DB_HOST": "db", "CONFIG": testConfig, "token": "abc"},
+		},
+		{
+			name:    "conflict with a source key",
+			keys:    map[string]string{"config.json:$.database.password": "token"},
+			wantErr: "key token conflicts with an existing key",
+		},
+		{
+			name:    "conflict with a string data key",
+			keys:    map[string]string{"config.json:$.database.password": "region"},
+			wantErr: "key region conflicts with an existing key",
+		},
+		{
+			name: "conflict with another extracted field",
+			keys: map[string]string{
+				"config.json:$.database.host":     "DB",
+				"config.json:$.database.password": "DB",
+			},
+			wantErr: "key config.json:$.database.password: key DB conflicts with an existing key",
+		},
+		{
+			name:    "invalid key",
+			keys:    map[string]string{"config.json:$.database.password": "db/password"},
+			wantErr: "invalid key db/password",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, _, err := mapKeys(internalv1alpha1.DstSecret{Keys: tt.keys}, srcSecret)
+			if len(tt.wantErr) > 0 {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("mapKeys() error = %v, want %q", err, tt.wantErr)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("mapKeys() error = %v", err)
+			}
+
+			if len(data) != len(tt.want) {
+				t.Errorf("mapKeys() keys = %v, want %v", keysOf(data), tt.want)
+			}
+
+			for key, val := range tt.want {
+				if string(data[key]) != val {
+					t.Errorf("key %s = %q, want %q", key, data[key], val)
+				}
+			}
+		})
+	}
+}
+
+func TestMapKeysFlatten(t *testing.T) {
+	srcSecret := &v1.Secret{Data: map[string][]byte{
+		"settings.yaml": []byte("database:\n  host: db\n"),
+		"database.host": []byte("conflict"),
+	}}
+
+	dstSecret := internalv1alpha1.DstSecret{
+		Flatten: []string{"settings.yaml"},
+		Keys:    map[string]string{"database.host": "DB_HOST"},
+	}
+
+	// The flattened key is renamed and conflicts with the renamed source key
+	if _, _, err := mapKeys(dstSecret, srcSecret); err == nil {
+		t.Error("mapKeys() error = nil, want the conflict of the flattened key")
+	}
+
+	delete(srcSecret.Data, "database.host")
+
+	data, _, err := mapKeys(dstSecret, srcSecret)
+	if err != nil {
+		t.Fatalf("mapKeys() error = %v", err)
+	}
+
+	if len(data) != 1 || string(data["DB_HOST"]) != "db" {
+		t.Errorf("mapKeys() = %v, want only DB_HOST", keysOf(data))
+	}
+}
+
+func TestMapKeysKeyMappings(t *testing.T) {
+	srcSecret := &v1.Secret{
+		Data:       map[string][]byte{"password": []byte("secret"), "user": []byte("admin")},
+		StringData: map[string]string{"host": "db"},
+	}
+
+	tests := []struct {
+		name     string
+		keys     map[string]string
+		mappings []internalv1alpha1.KeyMapping
+		want     map[string]string
+		wantErr  bool
+	}{
+		{
+			name:     "fan-out",
+			mappings: []internalv1alpha1.KeyMapping{{From: "password", To: []string{"DB_PASSWORD", "PGPASSWORD"}}},
+			want:     map[string]string{"DB_PASSWORD": "secret", "PGPASSWORD": "secret", "user": "admin", "host": "db"},
+		},
+		{
+			name:     "keep original",
+			mappings: []internalv1alpha1.KeyMapping{{From: "password", To: []string{"DB_PASSWORD"}, KeepOriginal: true}},
+			want:     map[string]string{"DB_PASSWORD": "secret", "password": "secret", "user": "admin", "host": "db"},
+		},
+		{
+			name:     "keep renamed original",
+			keys:     map[string]string{"password": "PASSWORD"},
+			mappings: []internalv1alpha1.KeyMapping{{From: "password", To: []string{"DB_PASSWORD"}, KeepOriginal: true}},
+			want:     map[string]string{"DB_PASSWORD": "secret", "PASSWORD": "secret", "user": "admin", "host": "db"},
+		},
+		{
+			name:     "string data",
+			mappings: []internalv1alpha1.KeyMapping{{From: "host", To: []string{"DB_HOST"}}},
+			want:     map[string]string{"DB_HOST": "db", "password": "secret", "user": "admin"},
+		},
+		{
+			name:     "default of a missing key",
+			mappings: []internalv1alpha1.KeyMapping{{From: "port", To: []string{"DB_PORT"}, Default: stringPtr("5432")}},
+			want:     map[string]string{"DB_PORT": "5432", "password": "secret", "user": "admin", "host": "db"},
+		},
+		{
+			name:     "default of an existing key",
+			mappings: []internalv1alpha1.KeyMapping{{From: "user", To: []string{"DB_USER"}, Default: stringPtr("root")}},
+			want:     map[string]string{"DB_USER": "admin", "password": "secret", "host": "db"},
+		},
+		{
+			name:     "missing key without default",
+			mappings: []internalv1alpha1.KeyMapping{{From: "port", To: []string{"DB_PORT"}}},
+			want:     map[string]string{"password": "secret", "user": "admin", "host": "db"},
+		},
+		{
+			name:     "conflict with a source key",
+			mappings: []internalv1alpha1.KeyMapping{{From: "password", To: []string{"user"}}},
+			wantErr:  true,
+		},
+		{
+			name:     "conflict with a renamed key",
+			keys:     map[string]string{"user": "DB_USER"},
+			mappings: []internalv1alpha1.KeyMapping{{From: "password", To: []string{"DB_USER"}}},
+			wantErr:  true,
+		},
+		{
+			name:     "conflict with a string data key",
+			mappings: []internalv1alpha1.KeyMapping{{From: "password", To: []string{"host"}}},
+			wantErr:  true,
+		},
+		{
+			name: "conflict with another mapping",
+			mappings: []internalv1alpha1.KeyMapping{
+				{From: "password", To: []string{"SECRET"}},
+				{From: "user", To: []string{"SECRET"}},
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, stringData, err := mapKeys(internalv1alpha1.DstSecret{Keys: tt.keys, KeyMappings: tt.mappings}, srcSecret)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("mapKeys() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if tt.wantErr {
+				return
+			}
+
+			merged := mergeStringData(data, stringData)
+			if len(merged) != len(tt.want) {
+				t.Errorf("mapKeys() keys = %v, want %v", keysOf(merged), tt.want)
+			}
+
+			for key, val := range tt.want {
+				if string(merged[key]) != val {
+					t.Errorf("key %s = %q, want %q", key, merged[key], val)
+				}
+			}
+		})
+	}
+}
+
+func keysOf(data map[string][]byte) []string {
+	keys := make([]string, 0, len(data))
+	for key := range data {
+		keys = append(keys, key)
+	}
+
+	return keys
+}
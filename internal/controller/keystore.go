@@ -0,0 +1,211 @@
+/*
+Copyright 2025 Edenlab
+*/
+
+package controller
+
+import (
+	"crypto"
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"fmt"
+	"unicode/utf8"
+
+	v1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	internalv1alpha1 "secrets-sync.operators.infra/api/v1alpha1"
+	"secrets-sync.operators.infra/internal/keystore"
+)
+
+const (
+	keystoreFormatPKCS12 = "pkcs12"
+	keystoreFormatJKS    = "jks"
+
+	defaultKeystoreAlias       = "tls"
+	defaultKeystorePasswordKey = "keystore-password"
+	caCertKey                  = "ca.crt"
+
+	// keystorePasswordKeyAnnotation records the password key on the destinations with keystores,
+	// so that their current keystores can be decoded and compared
+	keystorePasswordKeyAnnotation = "internal.edenlab.io/keystore-password-key"
+)
+
+// keystoreEqual compares the decoded keystores of the keys added by addKeystores
+var keystoreEqual = map[string]func(a, b []byte, password string) bool{
+	"keystore.p12":   keystore.PKCS12Equal,
+	"truststore.p12": keystore.PKCS12Equal,
+	"keystore.jks":   keystore.JKSEqual,
+	"truststore.jks": keystore.JKSEqual,
+}
+
+// addKeystores adds the keystores of the formats converted from the PEM keys of the destination,
+// the keystore requires tls.key and tls.crt and the truststore requires ca.crt
+func addKeystores(ks *internalv1alpha1.Keystore, data map[string][]byte) (map[string][]byte, error) {
+	_, hasKey := data[v1.TLSPrivateKeyKey]
+	_, hasCA := data[caCertKey]
+	if !hasKey && !hasCA {
+		return nil, fmt.Errorf("keystore requires key %s or %s", v1.TLSPrivateKeyKey, caCertKey)
+	}
+
+	alias := ks.Alias
+	if len(alias) == 0 {
+		alias = defaultKeystoreAlias
+	}
+
+	passwordKey := keystorePasswordKey(ks)
+	password, ok := data[passwordKey]
+	if !ok {
+		// Only the private key is secret, a password derived from the public CA could be computed by anyone
+		if !hasKey {
+			return nil, fmt.Errorf("keystore password key %s is required without key %s", passwordKey, v1.TLSPrivateKeyKey)
+		}
+
+		password = generatePassword(data[v1.TLSPrivateKeyKey])
+		data[passwordKey] = password
+	}
+
+	for _, format := range ks.Formats {
+		var (
+			encodeKeystore   = encodePKCS12
+			encodeTruststore = keystore.PKCS12Truststore
+			extension        = "p12"
+		)
+
+		switch format {
+		case keystoreFormatPKCS12:
+		case keystoreFormatJKS:
+			encodeKeystore, encodeTruststore, extension = keystore.JKS, keystore.JKSTruststore, "jks"
+		default:
+			return nil, fmt.Errorf("unknown keystore format %s", format)
+		}
+
+		if hasKey {
+			key, err := keystore.ParsePrivateKey(data[v1.TLSPrivateKeyKey])
+			if err != nil {
+				return nil, fmt.Errorf("key %s: %w", v1.TLSPrivateKeyKey, err)
+			}
+
+			chain, err := keystore.ParseCertificates(data[v1.TLSCertKey])
+			if err != nil {
+				return nil, fmt.Errorf("key %s: %w", v1.TLSCertKey, err)
+			}
+
+			if data["keystore."+extension], err = encodeKeystore(key, chain, alias, string(password)); err != nil {
+				return nil, err
+			}
+		}
+
+		if hasCA {
+			certs, err := keystore.ParseCertificates(data[caCertKey])
+			if err != nil {
+				return nil, fmt.Errorf("key %s: %w", caCertKey, err)
+			}
+
+			if data["truststore."+extension], err = encodeTruststore(certs, string(password)); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return data, nil
+}
+
+// generatePassword derives the password from the private key, so that it stays the same
+// as long as the key is not changed and the keystores are not rewritten on every sync
+func generatePassword(key []byte) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(defaultKeystorePasswordKey))
+
+	return []byte(base64.RawURLEncoding.EncodeToString(mac.Sum(nil)[:18]))
+}
+
+// keystorePasswordKey returns the key of the keystore password
+func keystorePasswordKey(ks *internalv1alpha1.Keystore) string {
+	if len(ks.PasswordKey) == 0 {
+		return defaultKeystorePasswordKey
+	}
+
+	return ks.PasswordKey
+}
+
+// encodePKCS12 encodes the PKCS#12 keystore, its key entry has no friendly name and the alias only applies to JKS
+func encodePKCS12(key crypto.PrivateKey, chain []*x509.Certificate, _, password string) ([]byte, error) {
+	return keystore.PKCS12(key, chain, password)
+}
+
+// markKeystores records the password key on the destination with keystores
+func markKeystores(obj client.Object, dstSecret internalv1alpha1.DstSecret) {
+	if dstSecret.Keystore == nil {
+		return
+	}
+
+	annotations := obj.GetAnnotations()
+	if annotations == nil {
+		annotations = make(map[string]string)
+	}
+
+	annotations[keystorePasswordKeyAnnotation] = keystorePasswordKey(dstSecret.Keystore)
+	obj.SetAnnotations(annotations)
+}
+
+// keepKeystores replaces the desired keystores with the current ones which hold the same entries
+// under the same password. The keystores are encoded with random salts, without it they would be
+// rewritten and the applications reloading them restarted on every sync.
+func keepKeystores(current, desired client.Object) {
+	passwordKey, ok := desired.GetAnnotations()[keystorePasswordKeyAnnotation]
+	if !ok {
+		return
+	}
+
+	currentData, desiredData := objectData(current), objectData(desired)
+
+	password, ok := desiredData[passwordKey]
+	if !ok || string(currentData[passwordKey]) != string(password) {
+		return
+	}
+
+	for key, equal := range keystoreEqual {
+		currentVal, ok := currentData[key]
+		if !ok {
+			continue
+		}
+
+		if desiredVal, ok := desiredData[key]; ok && equal(currentVal, desiredVal, string(password)) {
+			setObjectData(desired, key, currentVal)
+		}
+	}
+}
+
+// setObjectData sets the value of the key, the values of the config maps which are not valid UTF-8
+// are stored as binary data
+func setObjectData(obj client.Object, key string, val []byte) {
+	switch o := obj.(type) {
+	case *v1.Secret:
+		delete(o.StringData, key)
+		if o.Data == nil {
+			o.Data = make(map[string][]byte)
+		}
+
+		o.Data[key] = val
+	case *v1.ConfigMap:
+		delete(o.Data, key)
+		delete(o.BinaryData, key)
+
+		if utf8.Valid(val) {
+			if o.Data == nil {
+				o.Data = make(map[string]string)
+			}
+
+			o.Data[key] = string(val)
+		} else {
+			if o.BinaryData == nil {
+				o.BinaryData = make(map[string][]byte)
+			}
+
+			o.BinaryData[key] = val
+		}
+	}
+}
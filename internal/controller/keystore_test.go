@@ -0,0 +1,142 @@
+/*
+Copyright 2025 Edenlab
+*/
+
+package controller
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"testing"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+
+	internalv1alpha1 "secrets-sync.operators.infra/api/v1alpha1"
+)
+
+func TestGeneratePasswordDependsOnKey(t *testing.T) {
+	first, second := generatePassword([]byte("first key")), generatePassword([]byte("second key"))
+	if bytes.Equal(first, second) {
+		t.Error("passwords of different keys are equal")
+	}
+
+	if !bytes.Equal(first, generatePassword([]byte("first key"))) {
+		t.Error("password of the same key changed")
+	}
+}
+
+func TestAddKeystoresRequiresPasswordWithoutKey(t *testing.T) {
+	ks := &internalv1alpha1.Keystore{Formats: []internalv1alpha1.KeystoreFormat{keystoreFormatPKCS12}}
+
+	if _, err := addKeystores(ks, map[string][]byte{caCertKey: []byte("public CA")}); err == nil {
+		t.Error("addKeystores() generated a password from the public CA")
+	}
+
+	if _, err := addKeystores(ks, map[string][]byte{v1.TLSPrivateKeyKey: []byte("not a key")}); err == nil {
+		t.Error("addKeystores() accepted an invalid key")
+	}
+}
+
+// newTestTLSData returns a self-signed PEM key and certificate, the certificate is its own CA
+func newTestTLSData(t *testing.T) map[string][]byte {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "app"},
+		NotBefore:    time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+		NotAfter:     time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC),
+	}
+
+	certDER, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	keyDER, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cert := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: certDER})
+
+	return map[string][]byte{
+		v1.TLSPrivateKeyKey: pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: keyDER}),
+		v1.TLSCertKey:       cert,
+		caCertKey:           cert,
+	}
+}
+
+func TestKeepKeystores(t *testing.T) {
+	tlsData := newTestTLSData(t)
+	dstSecret := internalv1alpha1.DstSecret{Keystore: &internalv1alpha1.Keystore{
+		Formats: []internalv1alpha1.KeystoreFormat{keystoreFormatPKCS12, keystoreFormatJKS},
+	}}
+
+	// newDestination encodes the keystores again, with new random salts
+	newDestination := func(mutate func(data map[string][]byte)) *v1.Secret {
+		data := make(map[string][]byte, len(tlsData))
+		for key, val := range tlsData {
+			data[key] = val
+		}
+
+		if mutate != nil {
+			mutate(data)
+		}
+
+		withKeystores, err := addKeystores(dstSecret.Keystore, data)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		secret := newTestSecret(func(secret *v1.Secret) { secret.Data = withKeystores })
+		markKeystores(secret, dstSecret)
+
+		return secret
+	}
+
+	tests := []struct {
+		name       string
+		desired    func(data map[string][]byte)
+		wantAction string
+	}{
+		{name: "same entries"},
+		{
+			name:       "rotated password",
+			desired:    func(data map[string][]byte) { data[defaultKeystorePasswordKey] = []byte("rotated") },
+			wantAction: planActionUpdate,
+		},
+		{
+			name:       "renewed certificate",
+			desired:    func(data map[string][]byte) { data[caCertKey] = newTestTLSData(t)[v1.TLSCertKey] },
+			wantAction: planActionUpdate,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			current, desired := newDestination(nil), newDestination(tt.desired)
+			if bytes.Equal(current.Data["keystore.p12"], desired.Data["keystore.p12"]) {
+				t.Fatal("keystores are encoded with the same salts")
+			}
+
+			keepKeystores(current, desired)
+
+			if got := objectChange(current, desired); got != tt.wantAction {
+				t.Errorf("objectChange() = %q, want %q", got, tt.wantAction)
+			}
+		})
+	}
+}
@@ -0,0 +1,162 @@
+/*
+Copyright 2025 Edenlab
+*/
+
+package controller
+
+import (
+	goerrors "errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// defaultMaxDestinationBytes matches the size limit of a Secret
+	defaultMaxDestinationBytes = 1024 * 1024
+
+	conditionLimitExceeded = "LimitExceeded"
+	reasonLimitExceeded    = "LimitExceeded"
+	reasonWithinLimits     = "WithinLimits"
+)
+
+// limitError reports a destination or a SecretsSync exceeding a limit
+type limitError struct {
+	message string
+}
+
+func (e *limitError) Error() string {
+	return e.message
+}
+
+// limits are the effective limits of the SecretsSync, zero is unlimited
+type limits struct {
+	maxDestinations int64
+	maxBytes        int64
+	maxKeys         int64
+}
+
+// managerLimits returns the limits of the manager flags shared by the SecretsSyncs and the replicator
+func managerLimits(maxDestinations int, maxBytes int64, maxKeys int) limits {
+	l := limits{
+		maxDestinations: int64(maxDestinations),
+		maxBytes:        maxBytes,
+		maxKeys:         int64(maxKeys),
+	}
+
+	if maxBytes == 0 {
+		l.maxBytes = defaultMaxDestinationBytes
+	}
+
+	return l
+}
+
+// limits returns the lower of the SecretsSync and the manager limits
+func (r *SecretsSyncReconciler) limits() limits {
+	l := managerLimits(r.MaxDestinations, r.MaxDestinationBytes, r.MaxDestinationKeys)
+
+	specLimits := r.secretsSync.Spec.Limits
+	if specLimits == nil {
+		return l
+	}
+
+	if specLimits.MaxDestinations != nil {
+		l.maxDestinations = lowerLimit(l.maxDestinations, int64(*specLimits.MaxDestinations))
+	}
+
+	if specLimits.MaxBytes != nil {
+		l.maxBytes = lowerLimit(l.maxBytes, specLimits.MaxBytes.Value())
+	}
+
+	if specLimits.MaxKeys != nil {
+		l.maxKeys = lowerLimit(l.maxKeys, int64(*specLimits.MaxKeys))
+	}
+
+	return l
+}
+
+func lowerLimit(limit, specLimit int64) int64 {
+	if specLimit > 0 && (limit <= 0 || specLimit < limit) {
+		return specLimit
+	}
+
+	return limit
+}
+
+// checkDestinationLimits drops the destinations exceeding the size or the number of keys
+func checkDestinationLimits(l limits, objects []client.Object) ([]client.Object, []error) {
+	var (
+		kept []client.Object
+		errs []error
+	)
+
+	for _, obj := range objects {
+		data := objectData(obj)
+
+		size := int64(0)
+		for key, val := range data {
+			size += int64(len(key) + len(val))
+		}
+
+		switch {
+		case l.maxKeys > 0 && int64(len(data)) > l.maxKeys:
+			errs = append(errs, &limitError{message: fmt.Sprintf("%s %s/%s has %d keys, the limit is %d",
+				objectKind(obj), obj.GetNamespace(), obj.GetName(), len(data), l.maxKeys)})
+		case l.maxBytes > 0 && size > l.maxBytes:
+			errs = append(errs, &limitError{message: fmt.Sprintf("%s %s/%s has %d bytes, the limit is %d",
+				objectKind(obj), obj.GetNamespace(), obj.GetName(), size, l.maxBytes)})
+		default:
+			kept = append(kept, obj)
+		}
+	}
+
+	return kept, errs
+}
+
+// checkDestinationsLimit returns an error when the SecretsSync generates more destinations than allowed
+func checkDestinationsLimit(l limits, objects []client.Object) error {
+	if l.maxDestinations > 0 && int64(len(objects)) > l.maxDestinations {
+		return &limitError{message: fmt.Sprintf("%d destinations are generated, the limit is %d",
+			len(objects), l.maxDestinations)}
+	}
+
+	return nil
+}
+
+// updateLimitCondition sets the LimitExceeded condition from the limit errors of the generation
+func (r *SecretsSyncReconciler) updateLimitCondition(generateErrs []error) {
+	var exceeded []string
+
+	for _, err := range generateErrs {
+		var limitErr *limitError
+		if goerrors.As(err, &limitErr) {
+			exceeded = append(exceeded, err.Error())
+		}
+	}
+
+	conditions := append([]metav1.Condition(nil), r.secretsSync.Status.Conditions...)
+	if len(exceeded) > 0 {
+		sort.Strings(exceeded)
+		meta.SetStatusCondition(&conditions, metav1.Condition{
+			Type:               conditionLimitExceeded,
+			Status:             metav1.ConditionTrue,
+			ObservedGeneration: r.secretsSync.Generation,
+			Reason:             reasonLimitExceeded,
+			Message:            strings.Join(exceeded, "; "),
+		})
+	} else {
+		meta.SetStatusCondition(&conditions, metav1.Condition{
+			Type:               conditionLimitExceeded,
+			Status:             metav1.ConditionFalse,
+			ObservedGeneration: r.secretsSync.Generation,
+			Reason:             reasonWithinLimits,
+			Message:            "All destinations are within the limits",
+		})
+	}
+
+	r.saveConditions(conditions)
+}
@@ -0,0 +1,112 @@
+/*
+Copyright 2025 Edenlab
+*/
+
+package controller
+
+import (
+	"context"
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestCheckDestinationLimits(t *testing.T) {
+	small := newTestSecret(nil)
+	large := newTestSecret(func(secret *v1.Secret) {
+		secret.Name = "large"
+		secret.Data["payload"] = make([]byte, 100)
+	})
+	configMap := newConfigMap(metav1.ObjectMeta{Name: "config"}, map[string][]byte{"a": {1}, "b": {2}, "c": {3}})
+
+	tests := []struct {
+		name     string
+		limits   limits
+		wantKept int
+		wantErrs int
+	}{
+		{name: "unlimited", limits: limits{}, wantKept: 3},
+		{name: "bytes", limits: limits{maxBytes: 50}, wantKept: 2, wantErrs: 1},
+		{name: "keys", limits: limits{maxKeys: 2}, wantKept: 1, wantErrs: 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			kept, errs := checkDestinationLimits(tt.limits, []client.Object{small, large, configMap})
+			if len(kept) != tt.wantKept || len(errs) != tt.wantErrs {
+				t.Errorf("checkDestinationLimits() kept %d, errors %v, want %d kept and %d errors",
+					len(kept), errs, tt.wantKept, tt.wantErrs)
+			}
+		})
+	}
+}
+
+func TestLowerLimit(t *testing.T) {
+	tests := []struct {
+		limit, specLimit, want int64
+	}{
+		{limit: 0, specLimit: 0, want: 0},
+		{limit: 0, specLimit: 10, want: 10},
+		{limit: 100, specLimit: 10, want: 10},
+		{limit: 10, specLimit: 100, want: 10},
+	}
+
+	for _, tt := range tests {
+		if got := lowerLimit(tt.limit, tt.specLimit); got != tt.want {
+			t.Errorf("lowerLimit(%d, %d) = %d, want %d", tt.limit, tt.specLimit, got, tt.want)
+		}
+	}
+}
+
+func TestReplicatorLimits(t *testing.T) {
+	src := &v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "app",
+			Namespace:   "platform",
+			Annotations: map[string]string{replicateToAnnotation: "team-*"},
+		},
+		Data: map[string][]byte{"username": []byte("admin"), "password": []byte("secret")},
+		Type: v1.SecretTypeOpaque,
+	}
+
+	tests := []struct {
+		name         string
+		replicator   SecretReplicatorReconciler
+		wantReplicas int
+	}{
+		{name: "within limits", replicator: SecretReplicatorReconciler{MaxDestinations: 2}, wantReplicas: 2},
+		{name: "too many replicas", replicator: SecretReplicatorReconciler{MaxDestinations: 1}},
+		{name: "too many keys", replicator: SecretReplicatorReconciler{MaxDestinationKeys: 1}},
+		{name: "too many bytes", replicator: SecretReplicatorReconciler{MaxDestinationBytes: 10}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := tt.replicator
+			r.AccessPolicy = NewAccessPolicy("team-*")
+			r.Client = fake.NewClientBuilder().WithObjects(
+				&v1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "platform"}},
+				&v1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "team-a"}},
+				&v1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "team-b"}},
+				src.DeepCopy(),
+			).Build()
+
+			if _, err := r.Reconcile(context.Background(), ctrl.Request{NamespacedName: client.ObjectKeyFromObject(src)}); err != nil {
+				t.Fatalf("Reconcile() error = %v", err)
+			}
+
+			replicas := &v1.SecretList{}
+			if err := r.Client.List(context.Background(), replicas, client.MatchingLabels{ownerKind: replicatorOwnerKind}); err != nil {
+				t.Fatal(err)
+			}
+
+			if len(replicas.Items) != tt.wantReplicas {
+				t.Errorf("got %d replicas, want %d", len(replicas.Items), tt.wantReplicas)
+			}
+		})
+	}
+}
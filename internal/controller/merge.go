@@ -0,0 +1,145 @@
+/*
+Copyright 2025 Edenlab
+*/
+
+package controller
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	modeMerge = "Merge"
+
+	// fieldManager owns the keys and metadata applied to the merged destinations
+	fieldManager = "secrets-sync"
+
+	syncModeAnnotation    = "internal.edenlab.io/sync-mode"
+	managedKeysAnnotation = "internal.edenlab.io/managed-keys"
+
+	planActionRelease = "Release"
+)
+
+// markMerged marks the destination as merged into an object shared with other tools
+// and records its keys, so that removed keys are detected as drift
+func markMerged(obj client.Object) {
+	keys := make([]string, 0, len(objectData(obj)))
+	for key := range objectData(obj) {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	annotations := obj.GetAnnotations()
+	if annotations == nil {
+		annotations = make(map[string]string)
+	}
+
+	annotations[syncModeAnnotation] = modeMerge
+	annotations[managedKeysAnnotation] = strings.Join(keys, ",")
+	obj.SetAnnotations(annotations)
+}
+
+func isMerged(obj client.Object) bool {
+	return obj.GetAnnotations()[syncModeAnnotation] == modeMerge
+}
+
+// mergeObject applies the keys and metadata of the destination with server-side apply,
+// the keys set by other field managers are left untouched. Existing objects without
+// the owner labels are only merged into when adopt is set.
+func mergeObject(ctx context.Context, c client.Client, o owner, obj client.Object, adopt bool) (string, error) {
+	defObject := emptyObject(obj)
+	if err := c.Get(ctx, client.ObjectKeyFromObject(obj), defObject); err != nil {
+		if errors.IsNotFound(err) {
+			return planActionCreate, applyObject(ctx, c, obj)
+		}
+
+		return "", err
+	}
+
+	if !o.canWrite(defObject, obj, adopt) {
+		return "", errNotOwned
+	}
+
+	keepKeystores(defObject, obj)
+
+	if mergedEqual(defObject, obj) {
+		return "", nil
+	}
+
+	// The shared objects are never recreated
+	if isImmutable(defObject) {
+		return "", errImmutable
+	}
+
+	return planActionUpdate, applyObject(ctx, c, obj)
+}
+
+// mergedEqual reports whether the current object holds the desired keys and metadata
+func mergedEqual(current, desired client.Object) bool {
+	currentData := objectData(current)
+	for key, val := range objectData(desired) {
+		if currentVal, ok := currentData[key]; !ok || string(currentVal) != string(val) {
+			return false
+		}
+	}
+
+	return metadataEqual(current, desired) &&
+		current.GetAnnotations()[managedKeysAnnotation] == desired.GetAnnotations()[managedKeysAnnotation] &&
+		(len(secretType(desired)) == 0 || typeEqual(current, desired))
+}
+
+// applyObject applies a copy, so that the generated object is not replaced by the merged one
+func applyObject(ctx context.Context, c client.Client, obj client.Object) error {
+	return c.Patch(ctx, obj.DeepCopyObject().(client.Object), client.Apply,
+		client.FieldOwner(fieldManager), client.ForceOwnership)
+}
+
+// releaseObject applies an empty configuration, which removes the keys and metadata
+// of the field manager and keeps the object itself
+func releaseObject(ctx context.Context, c client.Client, obj client.Object) error {
+	empty := emptyObject(obj)
+	if objectKind(obj) == kindConfigMap {
+		empty.GetObjectKind().SetGroupVersionKind(configMapMeta.GroupVersionKind())
+	} else {
+		empty.GetObjectKind().SetGroupVersionKind(secretMeta.GroupVersionKind())
+	}
+
+	empty.SetName(obj.GetName())
+	empty.SetNamespace(obj.GetNamespace())
+
+	return client.IgnoreNotFound(c.Patch(ctx, empty, client.Apply, client.FieldOwner(fieldManager), client.ForceOwnership))
+}
+
+// removeObject deletes the orphaned destination, the merged ones are only released
+func removeObject(ctx context.Context, c client.Client, obj client.Object) error {
+	if isMerged(obj) {
+		return releaseObject(ctx, c, obj)
+	}
+
+	return c.Delete(ctx, obj)
+}
+
+// secretType returns the type of a Secret, it is empty for the merged secrets without an explicit type
+func secretType(obj client.Object) v1.SecretType {
+	if secret, ok := obj.(*v1.Secret); ok {
+		return secret.Type
+	}
+
+	return ""
+}
+
+// removeAction returns the planned action of the orphaned destination
+func removeAction(obj client.Object) string {
+	if isMerged(obj) {
+		return planActionRelease
+	}
+
+	return planActionDelete
+}
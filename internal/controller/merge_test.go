@@ -0,0 +1,158 @@
+/*
+Copyright 2025 Edenlab
+*/
+
+package controller
+
+import (
+	"context"
+
+	"github.com/go-logr/logr"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	internalv1alpha1 "secrets-sync.operators.infra/api/v1alpha1"
+)
+
+// The fake client has no server-side apply, the merged destinations are tested against the envtest API server
+var _ = Describe("Merged destinations", func() {
+	ctx := context.Background()
+
+	// newShared creates the secret of another tool which the destinations are merged into
+	newShared := func(name string) {
+		shared := &v1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: "default",
+				Labels:    map[string]string{"app.kubernetes.io/managed-by": "helm"},
+			},
+			Data: map[string][]byte{"values.yaml": []byte("replicas: 1")},
+			Type: v1.SecretTypeOpaque,
+		}
+
+		Expect(k8sClient.Create(ctx, shared)).To(Succeed())
+	}
+
+	// newMerged returns the merged destination of the owner
+	newMerged := func(o owner, name string) *v1.Secret {
+		desired := newTestSecret(func(secret *v1.Secret) {
+			secret.Name = name
+			secret.Labels = mergeMetadata(map[string]string{"team": "platform"}, o.labels())
+		})
+
+		markMerged(desired)
+
+		return desired
+	}
+
+	getSecret := func(name string) *v1.Secret {
+		secret := &v1.Secret{}
+		Expect(k8sClient.Get(ctx, client.ObjectKey{Namespace: "default", Name: name}, secret)).To(Succeed())
+
+		return secret
+	}
+
+	It("merges the keys into an adopted object and keeps the keys of other tools", func() {
+		o := owner{kind: "SecretsSync", name: "merge", namespace: "default"}
+		newShared("merge-adopted")
+		desired := newMerged(o, "merge-adopted")
+
+		Expect(mergeObject(ctx, k8sClient, o, desired, true)).To(Equal(planActionUpdate))
+
+		current := getSecret("merge-adopted")
+		Expect(current.Data).To(HaveKeyWithValue("values.yaml", []byte("replicas: 1")))
+		Expect(current.Data).To(HaveKeyWithValue("password", []byte("secret")))
+		Expect(current.Labels).To(HaveKeyWithValue("app.kubernetes.io/managed-by", "helm"))
+		Expect(o.owns(current)).To(BeTrue())
+		Expect(mergedEqual(current, desired)).To(BeTrue())
+
+		By("applying the same destination again")
+		Expect(mergeObject(ctx, k8sClient, o, desired, true)).To(BeEmpty())
+
+		By("restoring a key removed by another tool")
+		delete(current.Data, "password")
+		Expect(k8sClient.Update(ctx, current)).To(Succeed())
+		Expect(mergedEqual(getSecret("merge-adopted"), desired)).To(BeFalse())
+		Expect(mergeObject(ctx, k8sClient, o, desired, true)).To(Equal(planActionUpdate))
+		Expect(getSecret("merge-adopted").Data).To(HaveKeyWithValue("password", []byte("secret")))
+	})
+
+	It("creates the missing object", func() {
+		o := owner{kind: "SecretsSync", name: "merge", namespace: "default"}
+		desired := newMerged(o, "merge-created")
+
+		Expect(mergeObject(ctx, k8sClient, o, desired, false)).To(Equal(planActionCreate))
+		Expect(getSecret("merge-created").Data).To(HaveKeyWithValue("username", []byte("admin")))
+	})
+
+	It("never merges into unowned objects without adopt or into the objects of other owners", func() {
+		o := owner{kind: "SecretsSync", name: "merge", namespace: "default"}
+
+		newShared("merge-unowned")
+		_, err := mergeObject(ctx, k8sClient, o, newMerged(o, "merge-unowned"), false)
+		Expect(err).To(Equal(errNotOwned))
+
+		other := owner{kind: "SecretsSync", name: "other", namespace: "default"}
+		Expect(applyObject(ctx, k8sClient, newMerged(other, "merge-other"))).To(Succeed())
+		_, err = mergeObject(ctx, k8sClient, o, newMerged(o, "merge-other"), true)
+		Expect(err).To(Equal(errNotOwned))
+		Expect(other.owns(getSecret("merge-other"))).To(BeTrue())
+	})
+
+	It("releases the keys and metadata of the field manager and keeps the object", func() {
+		o := owner{kind: "SecretsSync", name: "merge", namespace: "default"}
+		newShared("merge-released")
+		desired := newMerged(o, "merge-released")
+		Expect(mergeObject(ctx, k8sClient, o, desired, true)).To(Equal(planActionUpdate))
+
+		Expect(releaseObject(ctx, k8sClient, desired)).To(Succeed())
+
+		current := getSecret("merge-released")
+		Expect(current.Data).To(Equal(map[string][]byte{"values.yaml": []byte("replicas: 1")}))
+		Expect(current.Labels).To(Equal(map[string]string{"app.kubernetes.io/managed-by": "helm"}))
+		Expect(current.Annotations).NotTo(HaveKey(syncModeAnnotation))
+		Expect(current.Annotations).NotTo(HaveKey(managedKeysAnnotation))
+	})
+
+	It("deletes the owned orphans and only releases the merged ones", func() {
+		o := owner{kind: "SecretsSync", name: "gc", namespace: "default"}
+
+		owned := newTestSecret(func(secret *v1.Secret) {
+			secret.Name = "gc-copy"
+			secret.Labels = o.labels()
+		})
+		Expect(k8sClient.Create(ctx, owned)).To(Succeed())
+
+		newShared("gc-merged")
+		Expect(mergeObject(ctx, k8sClient, o, newMerged(o, "gc-merged"), true)).To(Equal(planActionUpdate))
+
+		r := &SecretsSyncReconciler{
+			SystemInfo: &SystemInfo{
+				ctx:       ctx,
+				req:       ctrl.Request{NamespacedName: types.NamespacedName{Namespace: o.namespace, Name: o.name}},
+				reqLogger: logr.Discard(),
+				secretsSync: &internalv1alpha1.SecretsSync{
+					ObjectMeta: metav1.ObjectMeta{Name: o.name, Namespace: o.namespace},
+				},
+			},
+			Client: k8sClient,
+		}
+
+		// All destinations were removed from the SecretsSync
+		Expect(r.garbageCollector()).To(Succeed())
+
+		err := k8sClient.Get(ctx, client.ObjectKeyFromObject(owned), &v1.Secret{})
+		Expect(errors.IsNotFound(err)).To(BeTrue())
+
+		merged := getSecret("gc-merged")
+		Expect(merged.Data).To(Equal(map[string][]byte{"values.yaml": []byte("replicas: 1")}))
+		Expect(o.owns(merged)).To(BeFalse())
+	})
+})
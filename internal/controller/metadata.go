@@ -0,0 +1,175 @@
+/*
+Copyright 2025 Edenlab
+*/
+
+package controller
+
+import (
+	"sort"
+	"strings"
+
+	v1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	internalv1alpha1 "secrets-sync.operators.infra/api/v1alpha1"
+)
+
+const (
+	internalPrefix = "internal.edenlab.io/"
+
+	// The keys of the propagated and extra metadata, so that removed keys are detected as drift
+	managedLabelsAnnotation      = "internal.edenlab.io/managed-labels"
+	managedAnnotationsAnnotation = "internal.edenlab.io/managed-annotations"
+)
+
+// destinationMetadata returns the propagated and extra labels and annotations of the destination
+// together with the annotations listing their keys
+func destinationMetadata(dstSecret internalv1alpha1.DstSecret, srcSecret *v1.Secret) (map[string]string, map[string]string) {
+	var labelFilter, annotationFilter *internalv1alpha1.MetadataFilter
+	if dstSecret.Propagate != nil {
+		labelFilter, annotationFilter = dstSecret.Propagate.Labels, dstSecret.Propagate.Annotations
+	}
+
+	labels := mergeMetadata(filterMetadata(labelFilter, srcSecret.Labels), externalMetadata(dstSecret.Labels))
+	annotations := mergeMetadata(filterMetadata(annotationFilter, srcSecret.Annotations),
+		externalMetadata(dstSecret.Annotations))
+
+	labelKeys, annotationKeys := metadataKeys(labels), metadataKeys(annotations)
+	if len(labelKeys) > 0 {
+		annotations[managedLabelsAnnotation] = labelKeys
+	}
+
+	if len(annotationKeys) > 0 {
+		annotations[managedAnnotationsAnnotation] = annotationKeys
+	}
+
+	return labels, annotations
+}
+
+// filterMetadata returns the selected keys, the internal keys and the kubectl last applied configuration are skipped
+func filterMetadata(filter *internalv1alpha1.MetadataFilter, metadata map[string]string) map[string]string {
+	filtered := make(map[string]string)
+	if filter == nil {
+		return filtered
+	}
+
+	for key, val := range metadata {
+		if strings.HasPrefix(key, internalPrefix) || key == v1.LastAppliedConfigAnnotation {
+			continue
+		}
+
+		if (filter.All || matchKeys(filter.Allow, key)) && !matchKeys(filter.Deny, key) {
+			filtered[key] = val
+		}
+	}
+
+	return filtered
+}
+
+// externalMetadata drops the internal keys of the extra metadata, they are reserved for the operator
+// and would change the sync mode or trigger the replication of the destination
+func externalMetadata(metadata map[string]string) map[string]string {
+	external := make(map[string]string, len(metadata))
+	for key, val := range metadata {
+		if !strings.HasPrefix(key, internalPrefix) {
+			external[key] = val
+		}
+	}
+
+	return external
+}
+
+// matchKeys reports whether the key matches any of the keys or the prefixes ending with "*"
+func matchKeys(keys []string, key string) bool {
+	for _, val := range keys {
+		if val == key {
+			return true
+		}
+
+		if strings.HasSuffix(val, "*") && strings.HasPrefix(key, strings.TrimSuffix(val, "*")) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// mergeMetadata adds the extra keys on top of the propagated ones
+func mergeMetadata(metadata, extra map[string]string) map[string]string {
+	for key, val := range extra {
+		metadata[key] = val
+	}
+
+	return metadata
+}
+
+func metadataKeys(metadata map[string]string) string {
+	keys := make([]string, 0, len(metadata))
+	for key := range metadata {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	return strings.Join(keys, ",")
+}
+
+// preserveMetadata copies the labels and annotations of other tools from the current object to the desired one
+// before it replaces the current one. The internal keys and the keys managed before which are no longer desired
+// are dropped.
+func preserveMetadata(current, desired client.Object) {
+	currentAnnotations := current.GetAnnotations()
+
+	desired.SetLabels(unmanagedMetadata(current.GetLabels(), desired.GetLabels(),
+		currentAnnotations[managedLabelsAnnotation]))
+	desired.SetAnnotations(unmanagedMetadata(currentAnnotations, desired.GetAnnotations(),
+		currentAnnotations[managedAnnotationsAnnotation]))
+}
+
+// unmanagedMetadata adds the keys of the current metadata which are neither internal nor managed to the desired one
+func unmanagedMetadata(current, desired map[string]string, managedKeys string) map[string]string {
+	managed := splitList(managedKeys)
+
+	for key, val := range current {
+		if _, ok := desired[key]; ok || strings.HasPrefix(key, internalPrefix) || matchKeys(managed, key) {
+			continue
+		}
+
+		if desired == nil {
+			desired = make(map[string]string)
+		}
+
+		desired[key] = val
+	}
+
+	return desired
+}
+
+// addMetadataKey adds the key to the sorted list of the managed keys
+func addMetadataKey(keys, key string) string {
+	list := append(splitList(keys), key)
+	sort.Strings(list)
+
+	return strings.Join(list, ",")
+}
+
+// metadataEqual reports whether the current object has the desired labels and annotations
+// and no longer managed keys were removed from the desired ones
+func metadataEqual(current, desired client.Object) bool {
+	currentAnnotations, desiredAnnotations := current.GetAnnotations(), desired.GetAnnotations()
+
+	return containsMetadata(current.GetLabels(), desired.GetLabels()) &&
+		containsMetadata(currentAnnotations, desiredAnnotations) &&
+		currentAnnotations[managedLabelsAnnotation] == desiredAnnotations[managedLabelsAnnotation] &&
+		currentAnnotations[managedAnnotationsAnnotation] == desiredAnnotations[managedAnnotationsAnnotation]
+}
+
+func containsMetadata(metadata, desired map[string]string) bool {
+	for key, val := range desired {
+		if currentVal, ok := metadata[key]; !ok || currentVal != val {
+			return false
+		}
+	}
+
+	return true
+}
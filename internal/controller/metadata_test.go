@@ -0,0 +1,217 @@
+/*
+Copyright 2025 Edenlab
+*/
+
+package controller
+
+import (
+	"reflect"
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	internalv1alpha1 "secrets-sync.operators.infra/api/v1alpha1"
+)
+
+func TestMatchKeys(t *testing.T) {
+	tests := []struct {
+		keys []string
+		key  string
+		want bool
+	}{
+		{keys: []string{"team", "app"}, key: "app", want: true},
+		{keys: []string{"team", "app"}, key: "application"},
+		{keys: []string{"app.kubernetes.io/*"}, key: "app.kubernetes.io/name", want: true},
+		{keys: []string{"app.kubernetes.io/*"}, key: "app.kubernetes.io"},
+		{keys: []string{"*"}, key: "team", want: true},
+		{keys: []string{"team*"}, key: "team", want: true},
+		{keys: nil, key: "team"},
+	}
+
+	for _, tt := range tests {
+		if got := matchKeys(tt.keys, tt.key); got != tt.want {
+			t.Errorf("matchKeys(%v, %q) = %v, want %v", tt.keys, tt.key, got, tt.want)
+		}
+	}
+}
+
+func TestFilterMetadata(t *testing.T) {
+	metadata := map[string]string{
+		"team":                         "platform",
+		"app.kubernetes.io/name":       "app",
+		"app.kubernetes.io/managed-by": "helm",
+		"internal.edenlab.io/owner":    "sync",
+		v1.LastAppliedConfigAnnotation: "{}",
+	}
+
+	tests := []struct {
+		name   string
+		filter *internalv1alpha1.MetadataFilter
+		want   map[string]string
+	}{
+		{
+			name: "no filter",
+			want: map[string]string{},
+		},
+		{
+			name:   "allow",
+			filter: &internalv1alpha1.MetadataFilter{Allow: []string{"team"}},
+			want:   map[string]string{"team": "platform"},
+		},
+		{
+			name:   "allow prefix",
+			filter: &internalv1alpha1.MetadataFilter{Allow: []string{"app.kubernetes.io/*"}},
+			want:   map[string]string{"app.kubernetes.io/name": "app", "app.kubernetes.io/managed-by": "helm"},
+		},
+		{
+			name:   "deny wins over allow",
+			filter: &internalv1alpha1.MetadataFilter{Allow: []string{"app.kubernetes.io/*"}, Deny: []string{"app.kubernetes.io/managed-by"}},
+			want:   map[string]string{"app.kubernetes.io/name": "app"},
+		},
+		{
+			name:   "all but denied prefix",
+			filter: &internalv1alpha1.MetadataFilter{All: true, Deny: []string{"app.kubernetes.io/*"}},
+			want:   map[string]string{"team": "platform"},
+		},
+		{
+			name:   "internal and last applied keys are never copied",
+			filter: &internalv1alpha1.MetadataFilter{All: true, Allow: []string{"internal.edenlab.io/*", v1.LastAppliedConfigAnnotation}},
+			want:   map[string]string{"team": "platform", "app.kubernetes.io/name": "app", "app.kubernetes.io/managed-by": "helm"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := filterMetadata(tt.filter, metadata); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("filterMetadata() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDestinationMetadata(t *testing.T) {
+	srcSecret := &v1.Secret{ObjectMeta: metav1.ObjectMeta{
+		Labels:      map[string]string{"team": "platform", "tier": "backend"},
+		Annotations: map[string]string{"owner": "platform@example.com", "internal.edenlab.io/replicate-to": "*"},
+	}}
+
+	tests := []struct {
+		name            string
+		dstSecret       internalv1alpha1.DstSecret
+		wantLabels      map[string]string
+		wantAnnotations map[string]string
+	}{
+		{
+			name:            "nothing propagated",
+			dstSecret:       internalv1alpha1.DstSecret{},
+			wantLabels:      map[string]string{},
+			wantAnnotations: map[string]string{},
+		},
+		{
+			name: "propagated and extra keys",
+			dstSecret: internalv1alpha1.DstSecret{
+				Propagate: &internalv1alpha1.MetadataPropagation{
+					Labels:      &internalv1alpha1.MetadataFilter{Allow: []string{"team"}},
+					Annotations: &internalv1alpha1.MetadataFilter{All: true},
+				},
+				Labels:      map[string]string{"team": "data", "env": "prod"},
+				Annotations: map[string]string{"reloader": "true"},
+			},
+			wantLabels: map[string]string{"team": "data", "env": "prod"},
+			wantAnnotations: map[string]string{
+				"owner":                      "platform@example.com",
+				"reloader":                   "true",
+				managedLabelsAnnotation:      "env,team",
+				managedAnnotationsAnnotation: "owner,reloader",
+			},
+		},
+		{
+			name: "internal extra keys are dropped",
+			dstSecret: internalv1alpha1.DstSecret{
+				Labels: map[string]string{ownerName: "other", "env": "prod"},
+				Annotations: map[string]string{
+					syncModeAnnotation:    modeMerge,
+					replicateToAnnotation: "*",
+					reflectFromAnnotation: "platform/app",
+				},
+			},
+			wantLabels:      map[string]string{"env": "prod"},
+			wantAnnotations: map[string]string{managedLabelsAnnotation: "env"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			labels, annotations := destinationMetadata(tt.dstSecret, srcSecret)
+			if !reflect.DeepEqual(labels, tt.wantLabels) {
+				t.Errorf("destinationMetadata() labels = %v, want %v", labels, tt.wantLabels)
+			}
+
+			if !reflect.DeepEqual(annotations, tt.wantAnnotations) {
+				t.Errorf("destinationMetadata() annotations = %v, want %v", annotations, tt.wantAnnotations)
+			}
+		})
+	}
+}
+
+func TestMetadataEqual(t *testing.T) {
+	desired := newTestSecret(func(secret *v1.Secret) {
+		secret.Annotations[managedAnnotationsAnnotation] = "owner"
+		secret.Annotations["owner"] = "platform"
+	})
+
+	tests := []struct {
+		name    string
+		current func(secret *v1.Secret)
+		want    bool
+	}{
+		{name: "equal", want: true},
+		{
+			name: "keys of other tools",
+			current: func(secret *v1.Secret) {
+				secret.Labels["helm.sh/chart"] = "app"
+				secret.Annotations["checksum"] = "1"
+			},
+			want: true,
+		},
+		{name: "changed label", current: func(secret *v1.Secret) { secret.Labels["team"] = "data" }},
+		{name: "removed annotation", current: func(secret *v1.Secret) { delete(secret.Annotations, "owner") }},
+		{
+			// The label which is no longer propagated is only removed by an update
+			name: "no longer managed label",
+			current: func(secret *v1.Secret) {
+				secret.Labels["tier"] = "backend"
+				secret.Annotations[managedLabelsAnnotation] = "team,tier"
+			},
+		},
+		{
+			name:    "no longer managed annotation",
+			current: func(secret *v1.Secret) { secret.Annotations[managedAnnotationsAnnotation] = "owner,reloader" },
+		},
+		{name: "missing managed keys", current: func(secret *v1.Secret) { delete(secret.Annotations, managedLabelsAnnotation) }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			current := desired.DeepCopy()
+			if tt.current != nil {
+				tt.current(current)
+			}
+
+			if got := metadataEqual(current, desired); got != tt.want {
+				t.Errorf("metadataEqual() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAddMetadataKey(t *testing.T) {
+	if got, want := addMetadataKey("owner,team", "image"), "image,owner,team"; got != want {
+		t.Errorf("addMetadataKey() = %s, want %s", got, want)
+	}
+
+	if got, want := addMetadataKey("", "image"), "image"; got != want {
+		t.Errorf("addMetadataKey() = %s, want %s", got, want)
+	}
+}
@@ -0,0 +1,243 @@
+/*
+Copyright 2025 Edenlab
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	internalv1alpha1 "secrets-sync.operators.infra/api/v1alpha1"
+)
+
+const (
+	secretsProfileLabel = "internal.edenlab.io/secrets-profile"
+)
+
+// NamespaceReconciler instantiates a SecretsSync from the SecretsSyncProfile referenced
+// by the internal.edenlab.io/secrets-profile label of a namespace
+type NamespaceReconciler struct {
+	Scheme *runtime.Scheme
+	client.Client
+}
+
+//+kubebuilder:rbac:groups=internal.edenlab.io,resources=secretssyncprofiles,verbs=get;list;watch
+//+kubebuilder:rbac:groups=internal.edenlab.io,resources=secretssyncprofiles/status,verbs=get;update;patch
+//+kubebuilder:rbac:groups=internal.edenlab.io,resources=secretssyncprofiles/finalizers,verbs=update
+
+// Reconcile creates, updates or removes the SecretsSync of the profile in a single namespace
+func (r *NamespaceReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	var (
+		desired  *internalv1alpha1.SecretsSync
+		profiles = make(map[string]bool)
+	)
+
+	reqLogger := log.FromContext(ctx)
+
+	namespace := &v1.Namespace{}
+	if err := r.Client.Get(ctx, req.NamespacedName, namespace); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+
+	if profileName := namespace.Labels[secretsProfileLabel]; len(profileName) > 0 && namespace.DeletionTimestamp.IsZero() {
+		profiles[profileName] = true
+
+		profile := &internalv1alpha1.SecretsSyncProfile{}
+		if err := r.Client.Get(ctx, types.NamespacedName{Name: profileName}, profile); err != nil {
+			if !errors.IsNotFound(err) {
+				return ctrl.Result{}, err
+			}
+
+			reqLogger.Info(fmt.Sprintf("Secrets profile %s for namespace %s not exist", profileName, namespace.Name))
+		} else {
+			desired = &internalv1alpha1.SecretsSync{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels:    map[string]string{secretsProfileLabel: profile.Name},
+					Name:      profileSecretsSyncName(profile),
+					Namespace: namespace.Name,
+				},
+				Spec: *profile.Spec.Template.DeepCopy(),
+			}
+
+			// Used to ensure that the SecretsSync will be deleted when the profile is removed
+			if err := ctrl.SetControllerReference(profile, desired, r.Scheme); err != nil {
+				return ctrl.Result{}, err
+			}
+		}
+	}
+
+	listSecretsSyncs := &internalv1alpha1.SecretsSyncList{}
+	if err := r.Client.List(ctx, listSecretsSyncs, client.InNamespace(namespace.Name),
+		client.HasLabels{secretsProfileLabel}); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	for i, item := range listSecretsSyncs.Items {
+		if desired != nil && item.Name == desired.Name && item.Labels[secretsProfileLabel] == desired.Labels[secretsProfileLabel] {
+			continue
+		}
+
+		profiles[item.Labels[secretsProfileLabel]] = true
+		if err := r.Client.Delete(ctx, &listSecretsSyncs.Items[i]); client.IgnoreNotFound(err) != nil {
+			return ctrl.Result{}, err
+		}
+
+		reqLogger.Info(fmt.Sprintf("SecretsSync %s/%s of profile %s removed",
+			item.Namespace, item.Name, item.Labels[secretsProfileLabel]))
+	}
+
+	if desired != nil {
+		if err := r.applySecretsSync(ctx, desired); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
+	for profileName := range profiles {
+		if err := r.updateProfileStatus(ctx, profileName); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
+	return ctrl.Result{}, nil
+}
+
+func (r *NamespaceReconciler) applySecretsSync(ctx context.Context, desired *internalv1alpha1.SecretsSync) error {
+	reqLogger := log.FromContext(ctx)
+
+	secretsSync := &internalv1alpha1.SecretsSync{}
+	if err := r.Client.Get(ctx, client.ObjectKeyFromObject(desired), secretsSync); err != nil {
+		if !errors.IsNotFound(err) {
+			return err
+		}
+
+		if err := r.Client.Create(ctx, desired); err != nil {
+			return err
+		}
+
+		reqLogger.Info(fmt.Sprintf("SecretsSync %s/%s of profile %s created",
+			desired.Namespace, desired.Name, desired.Labels[secretsProfileLabel]))
+		return nil
+	}
+
+	if _, ok := secretsSync.Labels[secretsProfileLabel]; !ok {
+		reqLogger.Info(fmt.Sprintf("SecretsSync %s/%s already exists and is not created by a profile, skipped",
+			desired.Namespace, desired.Name))
+		return nil
+	}
+
+	if reflect.DeepEqual(secretsSync.Spec, desired.Spec) {
+		return nil
+	}
+
+	secretsSync.Spec = desired.Spec
+	if err := r.Client.Update(ctx, secretsSync); err != nil {
+		return err
+	}
+
+	reqLogger.Info(fmt.Sprintf("SecretsSync %s/%s of profile %s updated",
+		desired.Namespace, desired.Name, desired.Labels[secretsProfileLabel]))
+
+	return nil
+}
+
+func (r *NamespaceReconciler) updateProfileStatus(ctx context.Context, profileName string) error {
+	var namespaces []string
+
+	profile := &internalv1alpha1.SecretsSyncProfile{}
+	if err := r.Client.Get(ctx, types.NamespacedName{Name: profileName}, profile); err != nil {
+		return client.IgnoreNotFound(err)
+	}
+
+	listNamespaces := &v1.NamespaceList{}
+	if err := r.Client.List(ctx, listNamespaces, client.MatchingLabels{secretsProfileLabel: profileName}); err != nil {
+		return err
+	}
+
+	for _, namespace := range listNamespaces.Items {
+		if namespace.DeletionTimestamp.IsZero() {
+			namespaces = append(namespaces, namespace.Name)
+		}
+	}
+
+	sort.Strings(namespaces)
+	if reflect.DeepEqual(profile.Status.Namespaces, namespaces) {
+		return nil
+	}
+
+	profile.Status.Namespaces = namespaces
+
+	return r.Status().Update(ctx, profile)
+}
+
+// profileSecretsSyncName returns the name of the SecretsSync instantiated from the profile
+func profileSecretsSyncName(profile *internalv1alpha1.SecretsSyncProfile) string {
+	if len(profile.Spec.SecretsSyncName) > 0 {
+		return profile.Spec.SecretsSyncName
+	}
+
+	return profile.Name
+}
+
+func hasSecretsProfile(obj client.Object) bool {
+	_, ok := obj.GetLabels()[secretsProfileLabel]
+	return ok
+}
+
+// profileNamespaces maps a SecretsSyncProfile to the namespaces using it
+func (r *NamespaceReconciler) profileNamespaces(obj client.Object) []reconcile.Request {
+	var requests []reconcile.Request
+
+	listNamespaces := &v1.NamespaceList{}
+	if err := r.Client.List(context.Background(), listNamespaces,
+		client.MatchingLabels{secretsProfileLabel: obj.GetName()}); err != nil {
+		log.Log.Error(err, fmt.Sprintf("Unable to list namespaces of secrets profile %s", obj.GetName()))
+		return nil
+	}
+
+	for _, namespace := range listNamespaces.Items {
+		requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{Name: namespace.Name}})
+	}
+
+	return requests
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *NamespaceReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&v1.Namespace{}, builder.WithPredicates(predicate.Funcs{
+			CreateFunc: func(e event.CreateEvent) bool { return hasSecretsProfile(e.Object) },
+			// Removal of the label has to be handled as well to delete the SecretsSync
+			UpdateFunc: func(e event.UpdateEvent) bool {
+				return hasSecretsProfile(e.ObjectOld) || hasSecretsProfile(e.ObjectNew)
+			},
+			DeleteFunc:  func(event.DeleteEvent) bool { return false },
+			GenericFunc: func(e event.GenericEvent) bool { return hasSecretsProfile(e.Object) },
+		})).
+		Watches(&source.Kind{Type: &internalv1alpha1.SecretsSyncProfile{}},
+			handler.EnqueueRequestsFromMapFunc(r.profileNamespaces),
+			builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		Watches(&source.Kind{Type: &internalv1alpha1.SecretsSync{}},
+			handler.EnqueueRequestsFromMapFunc(func(obj client.Object) []reconcile.Request {
+				return []reconcile.Request{{NamespacedName: types.NamespacedName{Name: obj.GetNamespace()}}}
+			}),
+			builder.WithPredicates(predicate.GenerationChangedPredicate{}, predicate.NewPredicateFuncs(hasSecretsProfile))).
+		Complete(r)
+}
@@ -0,0 +1,180 @@
+/*
+Copyright 2025 Edenlab
+*/
+
+package controller
+
+import (
+	"unicode/utf8"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	kindSecret    = "Secret"
+	kindConfigMap = "ConfigMap"
+)
+
+var (
+	configMapMeta = metav1.TypeMeta{
+		APIVersion: "v1",
+		Kind:       kindConfigMap,
+	}
+)
+
+// objectKind returns the kind of the generated Secret or ConfigMap
+func objectKind(obj client.Object) string {
+	if _, ok := obj.(*v1.ConfigMap); ok {
+		return kindConfigMap
+	}
+
+	return kindSecret
+}
+
+// objectID identifies the object among the generated objects of all kinds and namespaces
+func objectID(obj client.Object) string {
+	return objectKind(obj) + "/" + obj.GetNamespace() + "/" + obj.GetName()
+}
+
+// emptyObject returns a new object of the same kind to read the current cluster state into
+func emptyObject(obj client.Object) client.Object {
+	if _, ok := obj.(*v1.ConfigMap); ok {
+		return &v1.ConfigMap{}
+	}
+
+	return &v1.Secret{}
+}
+
+// objectData returns the data of a Secret or ConfigMap as a single byte map,
+// the string data of a Secret is folded in the same way as the API server stores it
+func objectData(obj client.Object) map[string][]byte {
+	switch o := obj.(type) {
+	case *v1.Secret:
+		if len(o.StringData) > 0 {
+			return mergeStringData(o.Data, o.StringData)
+		}
+
+		return o.Data
+	case *v1.ConfigMap:
+		data := make(map[string][]byte, len(o.Data)+len(o.BinaryData))
+		for key, val := range o.BinaryData {
+			data[key] = val
+		}
+
+		for key, val := range o.Data {
+			data[key] = []byte(val)
+		}
+
+		return data
+	}
+
+	return nil
+}
+
+// dataEqual reports whether the data of the current object matches the desired one
+func dataEqual(current, desired client.Object) bool {
+	currentData, desiredData := objectData(current), objectData(desired)
+	if len(currentData) != len(desiredData) {
+		return false
+	}
+
+	for key, val := range desiredData {
+		if currentVal, ok := currentData[key]; !ok || string(currentVal) != string(val) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// typeEqual reports whether the secret type of the current object matches the desired one,
+// the type is immutable, so such objects have to be recreated
+func typeEqual(current, desired client.Object) bool {
+	currentSecret, ok := current.(*v1.Secret)
+	if !ok {
+		return true
+	}
+
+	desiredType := desired.(*v1.Secret).Type
+	if len(desiredType) == 0 {
+		desiredType = v1.SecretTypeOpaque
+	}
+
+	return currentSecret.Type == desiredType
+}
+
+// immutableEqual reports whether both objects are immutable or mutable
+func immutableEqual(current, desired client.Object) bool {
+	return isImmutable(current) == isImmutable(desired)
+}
+
+func isImmutable(obj client.Object) bool {
+	switch o := obj.(type) {
+	case *v1.Secret:
+		return o.Immutable != nil && *o.Immutable
+	case *v1.ConfigMap:
+		return o.Immutable != nil && *o.Immutable
+	}
+
+	return false
+}
+
+// objectChange compares the normalized current and desired objects and returns the action which brings
+// the current object in sync: empty when it is in sync, Update when the data, the managed metadata or
+// the immutability can be changed in place and Recreate when the type or an immutable object differs
+func objectChange(current, desired client.Object) string {
+	if !typeEqual(current, desired) {
+		return planActionRecreate
+	}
+
+	dataChanged, immutableChanged := !dataEqual(current, desired), !immutableEqual(current, desired)
+	if !dataChanged && !immutableChanged && metadataEqual(current, desired) {
+		return ""
+	}
+
+	// The metadata of immutable objects can still be updated
+	if isImmutable(current) && (dataChanged || immutableChanged) {
+		return planActionRecreate
+	}
+
+	return planActionUpdate
+}
+
+// configMapToSecret represents a source ConfigMap as an Opaque secret,
+// so that both kinds of sources pass through the same generation steps
+func configMapToSecret(configMap *v1.ConfigMap) *v1.Secret {
+	return &v1.Secret{
+		TypeMeta:   secretMeta,
+		ObjectMeta: *configMap.ObjectMeta.DeepCopy(),
+		Data:       objectData(configMap),
+		Type:       v1.SecretTypeOpaque,
+	}
+}
+
+// newConfigMap builds a destination ConfigMap, values which are not valid UTF-8 are stored as binary data
+func newConfigMap(meta metav1.ObjectMeta, data map[string][]byte) *v1.ConfigMap {
+	configMap := &v1.ConfigMap{
+		TypeMeta:   configMapMeta,
+		ObjectMeta: meta,
+	}
+
+	for key, val := range data {
+		if utf8.Valid(val) {
+			if configMap.Data == nil {
+				configMap.Data = make(map[string]string)
+			}
+
+			configMap.Data[key] = string(val)
+		} else {
+			if configMap.BinaryData == nil {
+				configMap.BinaryData = make(map[string][]byte)
+			}
+
+			configMap.BinaryData[key] = val
+		}
+	}
+
+	return configMap
+}
@@ -0,0 +1,487 @@
+/*
+Copyright 2025 Edenlab
+*/
+
+package controller
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	internalv1alpha1 "secrets-sync.operators.infra/api/v1alpha1"
+)
+
+func newTestSecret(mutate func(secret *v1.Secret)) *v1.Secret {
+	secret := &v1.Secret{
+		TypeMeta: secretMeta,
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "app",
+			Namespace: "default",
+			Labels: map[string]string{
+				ownerKind:      "SecretsSync",
+				ownerName:      "app",
+				ownerNamespace: "default",
+				"team":         "platform",
+			},
+			Annotations: map[string]string{
+				managedLabelsAnnotation: "team",
+			},
+		},
+		Data: map[string][]byte{
+			"username": []byte("admin"),
+			"password": []byte("secret"),
+		},
+		Type: v1.SecretTypeOpaque,
+	}
+
+	if mutate != nil {
+		mutate(secret)
+	}
+
+	return secret
+}
+
+func TestObjectChange(t *testing.T) {
+	immutable := true
+
+	tests := []struct {
+		name    string
+		current client.Object
+		desired client.Object
+		want    string
+	}{
+		{
+			name:    "in sync",
+			current: newTestSecret(nil),
+			desired: newTestSecret(nil),
+			want:    "",
+		},
+		{
+			name:    "string data folded into data",
+			current: newTestSecret(nil),
+			desired: newTestSecret(func(secret *v1.Secret) {
+				delete(secret.Data, "password")
+				secret.StringData = map[string]string{"password": "secret"}
+			}),
+			want: "",
+		},
+		{
+			name:    "empty desired type matches Opaque",
+			current: newTestSecret(nil),
+			desired: newTestSecret(func(secret *v1.Secret) { secret.Type = "" }),
+			want:    "",
+		},
+		{
+			name: "labels and annotations of other tools are ignored",
+			current: newTestSecret(func(secret *v1.Secret) {
+				secret.Labels["app.kubernetes.io/managed-by"] = "helm"
+				secret.Annotations["meta.helm.sh/release-name"] = "app"
+			}),
+			desired: newTestSecret(nil),
+			want:    "",
+		},
+		{
+			name:    "changed value",
+			current: newTestSecret(nil),
+			desired: newTestSecret(func(secret *v1.Secret) { secret.Data["password"] = []byte("rotated") }),
+			want:    planActionUpdate,
+		},
+		{
+			name:    "removed key",
+			current: newTestSecret(nil),
+			desired: newTestSecret(func(secret *v1.Secret) { delete(secret.Data, "password") }),
+			want:    planActionUpdate,
+		},
+		{
+			name:    "added key",
+			current: newTestSecret(nil),
+			desired: newTestSecret(func(secret *v1.Secret) { secret.Data["host"] = []byte("db") }),
+			want:    planActionUpdate,
+		},
+		{
+			name:    "changed managed label",
+			current: newTestSecret(nil),
+			desired: newTestSecret(func(secret *v1.Secret) { secret.Labels["team"] = "payments" }),
+			want:    planActionUpdate,
+		},
+		{
+			name:    "removed managed label",
+			current: newTestSecret(nil),
+			desired: newTestSecret(func(secret *v1.Secret) {
+				delete(secret.Labels, "team")
+				delete(secret.Annotations, managedLabelsAnnotation)
+			}),
+			want: planActionUpdate,
+		},
+		{
+			name:    "added annotation",
+			current: newTestSecret(nil),
+			desired: newTestSecret(func(secret *v1.Secret) {
+				secret.Annotations["reloader.stakater.com/match"] = "true"
+				secret.Annotations[managedAnnotationsAnnotation] = "reloader.stakater.com/match"
+			}),
+			want: planActionUpdate,
+		},
+		{
+			name:    "changed type",
+			current: newTestSecret(nil),
+			desired: newTestSecret(func(secret *v1.Secret) { secret.Type = v1.SecretTypeBasicAuth }),
+			want:    planActionRecreate,
+		},
+		{
+			name:    "made immutable",
+			current: newTestSecret(nil),
+			desired: newTestSecret(func(secret *v1.Secret) { secret.Immutable = &immutable }),
+			want:    planActionUpdate,
+		},
+		{
+			name:    "made mutable",
+			current: newTestSecret(func(secret *v1.Secret) { secret.Immutable = &immutable }),
+			desired: newTestSecret(nil),
+			want:    planActionRecreate,
+		},
+		{
+			name:    "changed value of immutable object",
+			current: newTestSecret(func(secret *v1.Secret) { secret.Immutable = &immutable }),
+			desired: newTestSecret(func(secret *v1.Secret) {
+				secret.Immutable = &immutable
+				secret.Data["password"] = []byte("rotated")
+			}),
+			want: planActionRecreate,
+		},
+		{
+			name:    "changed label of immutable object",
+			current: newTestSecret(func(secret *v1.Secret) { secret.Immutable = &immutable }),
+			desired: newTestSecret(func(secret *v1.Secret) {
+				secret.Immutable = &immutable
+				secret.Labels["team"] = "payments"
+			}),
+			want: planActionUpdate,
+		},
+		{
+			name: "config map binary data",
+			current: &v1.ConfigMap{
+				BinaryData: map[string][]byte{"cert.der": {0xff, 0xfe}},
+				Data:       map[string]string{"host": "db"},
+			},
+			desired: newConfigMap(metav1.ObjectMeta{}, map[string][]byte{
+				"cert.der": {0xff, 0xfe},
+				"host":     []byte("db"),
+			}),
+			want: "",
+		},
+		{
+			name:    "config map changed value",
+			current: &v1.ConfigMap{Data: map[string]string{"host": "db"}},
+			desired: newConfigMap(metav1.ObjectMeta{}, map[string][]byte{"host": []byte("replica")}),
+			want:    planActionUpdate,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := objectChange(tt.current, tt.desired); got != tt.want {
+				t.Errorf("objectChange() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGenerateObjectsFoldsStringData(t *testing.T) {
+	srcSecret := &v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "source"},
+		Data:       map[string][]byte{"username": []byte("admin")},
+		StringData: map[string]string{"password": "secret"},
+	}
+
+	objects, err := generateObjects(owner{kind: "SecretsSync", name: "app", namespace: "default"},
+		internalv1alpha1.SrcSecret{SrcNamespace: "source", DstSecrets: []internalv1alpha1.DstSecret{{}}}, srcSecret)
+	if err != nil {
+		t.Fatalf("generateObjects() error = %v", err)
+	}
+
+	secret := objects[0].(*v1.Secret)
+	if len(secret.StringData) > 0 {
+		t.Errorf("StringData = %v, want it folded into Data", secret.StringData)
+	}
+
+	if string(secret.Data["password"]) != "secret" || string(secret.Data["username"]) != "admin" {
+		t.Errorf("Data = %v, want username and password", secret.Data)
+	}
+}
+
+func TestGenerateObjectsRejectsImmutableMerge(t *testing.T) {
+	srcSecret := &v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "source"},
+		Data:       map[string][]byte{"password": []byte("secret")},
+	}
+
+	objects, err := generateObjects(owner{kind: "SecretsSync", name: "app", namespace: "default"},
+		internalv1alpha1.SrcSecret{SrcNamespace: "source", DstSecrets: []internalv1alpha1.DstSecret{
+			{Name: "app-helm-values", Mode: modeMerge, Immutable: true},
+			{Name: "app-copy", Immutable: true},
+		}}, srcSecret)
+	if err == nil || !strings.Contains(err.Error(), "destination app-helm-values: immutable is not supported") {
+		t.Errorf("generateObjects() error = %v, want the immutable merged destination", err)
+	}
+
+	if len(objects) != 1 || objects[0].GetName() != "app-copy" {
+		t.Errorf("generateObjects() = %d objects, want only app-copy", len(objects))
+	}
+}
+
+func TestGenerateObjectsRejectsConfigMapType(t *testing.T) {
+	srcSecret := &v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "source"},
+		Data:       map[string][]byte{"username": []byte("admin"), "password": []byte("secret")},
+	}
+
+	objects, err := generateObjects(owner{kind: "SecretsSync", name: "app", namespace: "default"},
+		internalv1alpha1.SrcSecret{SrcNamespace: "source", DstSecrets: []internalv1alpha1.DstSecret{
+			{Name: "app-config", Kind: kindConfigMap, Type: v1.SecretTypeBasicAuth},
+			{Name: "app-copy", Type: v1.SecretTypeBasicAuth},
+		}}, srcSecret)
+	if err == nil || !strings.Contains(err.Error(), "destination app-config: type is not supported") {
+		t.Errorf("generateObjects() error = %v, want the typed ConfigMap destination", err)
+	}
+
+	if len(objects) != 1 || objects[0].GetName() != "app-copy" {
+		t.Errorf("generateObjects() = %d objects, want only app-copy", len(objects))
+	}
+}
+
+func TestCheckImmutable(t *testing.T) {
+	immutable := true
+	current := newTestSecret(func(secret *v1.Secret) { secret.Immutable = &immutable })
+
+	tests := []struct {
+		name      string
+		dstSecret internalv1alpha1.DstSecret
+		change    func(secret *v1.Secret)
+		wantErr   bool
+	}{
+		{
+			name:      "recreated by default",
+			dstSecret: internalv1alpha1.DstSecret{Immutable: true},
+			change:    func(secret *v1.Secret) { secret.Data["password"] = []byte("rotated") },
+		},
+		{
+			name:      "kept with the Keep strategy",
+			dstSecret: internalv1alpha1.DstSecret{Immutable: true, ReplaceStrategy: replaceStrategyKeep},
+			change:    func(secret *v1.Secret) { secret.Data["password"] = []byte("rotated") },
+			wantErr:   true,
+		},
+		{
+			name:      "metadata updated with the Keep strategy",
+			dstSecret: internalv1alpha1.DstSecret{Immutable: true, ReplaceStrategy: replaceStrategyKeep},
+			change:    func(secret *v1.Secret) { secret.Labels["team"] = "payments" },
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			desired := newTestSecret(tt.change)
+			markImmutable(desired, tt.dstSecret)
+			current := current.DeepCopy()
+			current.Annotations = desired.Annotations
+
+			err := checkImmutable(current, desired, objectChange(current, desired))
+			if (err != nil) != tt.wantErr {
+				t.Errorf("checkImmutable() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestDropCollisions(t *testing.T) {
+	secret := func(namespace, name string) client.Object {
+		return &v1.Secret{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace}}
+	}
+
+	configMap := newConfigMap(metav1.ObjectMeta{Name: "app", Namespace: "default"}, nil)
+
+	unique := []client.Object{secret("default", "app"), secret("prod", "app"), configMap}
+	if got, err := dropCollisions(unique); err != nil || len(got) != len(unique) {
+		t.Errorf("dropCollisions() = %d objects, %v, want all objects", len(got), err)
+	}
+
+	got, err := dropCollisions([]client.Object{
+		secret("default", "app"),
+		secret("prod", "db"),
+		secret("default", "app"),
+		configMap,
+		secret("prod", "db"),
+	})
+	if err == nil {
+		t.Fatal("dropCollisions() error = nil, want the collisions")
+	}
+
+	if len(got) != 1 || objectKind(got[0]) != kindConfigMap {
+		t.Errorf("dropCollisions() = %v, want only the config map", got)
+	}
+
+	for _, id := range []string{"default/app", "prod/db"} {
+		if !strings.Contains(err.Error(), id) {
+			t.Errorf("error %q does not list %s", err, id)
+		}
+	}
+}
+
+func TestSyncObjectKeepsMetadataOfOtherTools(t *testing.T) {
+	o := owner{kind: "SecretsSync", name: "app", namespace: "default"}
+
+	current := newTestSecret(func(secret *v1.Secret) {
+		secret.Labels["app.kubernetes.io/managed-by"] = "helm"
+		secret.Labels["tier"] = "db"
+		secret.Annotations["meta.helm.sh/release-name"] = "app"
+		secret.Annotations[managedLabelsAnnotation] = "team,tier"
+		secret.Annotations[imagePullServiceAccountsAnnotation] = "default"
+		secret.Finalizers = []string{"example.com/backup"}
+	})
+
+	// The tier label is no longer propagated and the value is rotated
+	desired := newTestSecret(func(secret *v1.Secret) { secret.Data["password"] = []byte("rotated") })
+
+	c := fake.NewClientBuilder().WithObjects(current).Build()
+	action, err := syncObject(context.Background(), c, o, desired, false)
+	if err != nil || action != planActionUpdate {
+		t.Fatalf("syncObject() = %q, %v, want %q", action, err, planActionUpdate)
+	}
+
+	updated := &v1.Secret{}
+	if err := c.Get(context.Background(), client.ObjectKeyFromObject(desired), updated); err != nil {
+		t.Fatal(err)
+	}
+
+	if updated.Labels["app.kubernetes.io/managed-by"] != "helm" || updated.Annotations["meta.helm.sh/release-name"] != "app" {
+		t.Errorf("metadata of other tools was removed: labels %v, annotations %v", updated.Labels, updated.Annotations)
+	}
+
+	if _, ok := updated.Labels["tier"]; ok {
+		t.Error("label tier is no longer managed and was kept")
+	}
+
+	if _, ok := updated.Annotations[imagePullServiceAccountsAnnotation]; ok {
+		t.Error("internal annotation which is no longer desired was kept")
+	}
+
+	if updated.Labels["team"] != "platform" || string(updated.Data["password"]) != "rotated" {
+		t.Errorf("desired labels %v or data were not applied", updated.Labels)
+	}
+
+	if len(updated.Finalizers) != 1 {
+		t.Errorf("finalizers = %v, want the finalizer of the other tool", updated.Finalizers)
+	}
+}
+
+func TestUnmanagedMetadata(t *testing.T) {
+	current := map[string]string{
+		"helm":                  "app",
+		"team":                  "platform",
+		"propagated/a":          "1",
+		internalPrefix + "mode": "Merge",
+	}
+
+	got := unmanagedMetadata(current, map[string]string{"team": "payments"}, "propagated/a,team")
+	want := map[string]string{"helm": "app", "team": "payments"}
+
+	if len(got) != len(want) {
+		t.Fatalf("unmanagedMetadata() = %v, want %v", got, want)
+	}
+
+	for key, val := range want {
+		if got[key] != val {
+			t.Errorf("unmanagedMetadata()[%s] = %q, want %q", key, got[key], val)
+		}
+	}
+
+	if got := unmanagedMetadata(map[string]string{"helm": "app"}, nil, ""); got["helm"] != "app" {
+		t.Errorf("unmanagedMetadata() into nil = %v, want the helm key", got)
+	}
+}
+
+func TestNewConfigMap(t *testing.T) {
+	tests := []struct {
+		name           string
+		data           map[string][]byte
+		wantData       map[string]string
+		wantBinaryData map[string][]byte
+	}{
+		{name: "no data"},
+		{
+			name:     "text values",
+			data:     map[string][]byte{"host": []byte("db"), "empty": {}},
+			wantData: map[string]string{"host": "db", "empty": ""},
+		},
+		{
+			name:           "binary values",
+			data:           map[string][]byte{"cert.der": {0xff, 0xfe}},
+			wantBinaryData: map[string][]byte{"cert.der": {0xff, 0xfe}},
+		},
+		{
+			name:           "text and binary values",
+			data:           map[string][]byte{"host": []byte("db"), "cert.der": {0x30, 0x82, 0xc3}},
+			wantData:       map[string]string{"host": "db"},
+			wantBinaryData: map[string][]byte{"cert.der": {0x30, 0x82, 0xc3}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := newConfigMap(metav1.ObjectMeta{Name: "app", Namespace: "default"}, tt.data)
+
+			if got.Kind != kindConfigMap || got.Name != "app" || got.Namespace != "default" {
+				t.Errorf("newConfigMap() = %s %s/%s, want ConfigMap default/app", got.Kind, got.Namespace, got.Name)
+			}
+
+			if !reflect.DeepEqual(got.Data, tt.wantData) {
+				t.Errorf("Data = %v, want %v", got.Data, tt.wantData)
+			}
+
+			if !reflect.DeepEqual(got.BinaryData, tt.wantBinaryData) {
+				t.Errorf("BinaryData = %v, want %v", got.BinaryData, tt.wantBinaryData)
+			}
+
+			// The split data is read back unchanged
+			if len(tt.data) > 0 && !reflect.DeepEqual(objectData(got), tt.data) {
+				t.Errorf("objectData() = %v, want %v", objectData(got), tt.data)
+			}
+		})
+	}
+}
+
+func TestConfigMapToSecret(t *testing.T) {
+	configMap := &v1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "platform", Labels: map[string]string{"team": "platform"}},
+		Data:       map[string]string{"host": "db"},
+		BinaryData: map[string][]byte{"cert.der": {0xff, 0xfe}},
+	}
+
+	got := configMapToSecret(configMap)
+
+	if got.Kind != kindSecret || got.Type != v1.SecretTypeOpaque {
+		t.Errorf("configMapToSecret() = %s of type %s, want an Opaque Secret", got.Kind, got.Type)
+	}
+
+	wantData := map[string][]byte{"host": []byte("db"), "cert.der": {0xff, 0xfe}}
+	if !reflect.DeepEqual(got.Data, wantData) {
+		t.Errorf("Data = %v, want %v", got.Data, wantData)
+	}
+
+	if got.Name != "app" || got.Namespace != "platform" || got.Labels["team"] != "platform" {
+		t.Errorf("metadata = %s/%s %v, want the metadata of the ConfigMap", got.Namespace, got.Name, got.Labels)
+	}
+
+	// The metadata is copied, so that the generation steps do not modify the cached source
+	got.Labels["team"] = "payments"
+	if configMap.Labels["team"] != "platform" {
+		t.Errorf("source label = %s, want it unchanged", configMap.Labels["team"])
+	}
+}
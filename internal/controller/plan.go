@@ -0,0 +1,161 @@
+/*
+Copyright 2025 Edenlab
+*/
+
+package controller
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"sort"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	internalv1alpha1 "secrets-sync.operators.infra/api/v1alpha1"
+)
+
+const (
+	planActionCreate   = "Create"
+	planActionUpdate   = "Update"
+	planActionDelete   = "Delete"
+	planActionRecreate = "Recreate"
+)
+
+func (r *SecretsSyncReconciler) dryRun() bool {
+	return r.DryRun || r.secretsSync.Spec.DryRun
+}
+
+// planHashKeySize is the size of the generated key of the plan hashes
+const planHashKeySize = 32
+
+// LoadPlanHashKey reads the key of the plan hashes from the file,
+// without the file a random key is generated and the hashes change on every restart
+func LoadPlanHashKey(path string) ([]byte, error) {
+	if len(path) == 0 {
+		key := make([]byte, planHashKeySize)
+		if _, err := rand.Read(key); err != nil {
+			return nil, err
+		}
+
+		return key, nil
+	}
+
+	key, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(key) < planHashKeySize {
+		return nil, fmt.Errorf("plan hash key %s is shorter than %d bytes", path, planHashKeySize)
+	}
+
+	return key, nil
+}
+
+// planChanges compares the generated objects with the cluster state and returns the changes which Reconcile
+// would apply, the deletions are planned only with collectGarbage set. The existing objects which the sync
+// would skip as not owned are returned separately.
+func (r *SecretsSyncReconciler) planChanges(collectGarbage bool, objects ...client.Object) ([]internalv1alpha1.PlannedChange, []string, error) {
+	var (
+		plan    []internalv1alpha1.PlannedChange
+		skipped []string
+		o       = r.owner()
+	)
+
+	if collectGarbage {
+		orphans, err := orphanObjects(r.ctx, r.Client, o, objects...)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		for _, item := range orphans {
+			plan = append(plan, r.plannedChange(removeAction(item), item))
+		}
+	}
+
+	for _, obj := range objects {
+		defObject := emptyObject(obj)
+		if err := r.Client.Get(r.ctx, client.ObjectKeyFromObject(obj), defObject); err != nil {
+			if errors.IsNotFound(err) {
+				plan = append(plan, r.plannedChange(planActionCreate, obj))
+				continue
+			}
+
+			return nil, nil, err
+		}
+
+		// Unowned objects are only adopted in the namespace of the SecretsSync, like in the sync
+		if !o.canWrite(defObject, obj, obj.GetNamespace() == r.req.Namespace) {
+			skipped = append(skipped, fmt.Sprintf("%s: not owned", objectID(obj)))
+			continue
+		}
+
+		keepKeystores(defObject, obj)
+
+		if isMerged(obj) {
+			if !mergedEqual(defObject, obj) && !isImmutable(defObject) {
+				plan = append(plan, r.plannedChange(planActionUpdate, obj))
+			}
+
+			continue
+		}
+
+		action := objectChange(defObject, obj)
+		if len(action) > 0 && checkImmutable(defObject, obj, action) == nil {
+			plan = append(plan, r.plannedChange(action, obj))
+		}
+	}
+
+	// Sources are iterated in a random order, keep the plan stable between syncs
+	sort.Slice(plan, func(i, j int) bool {
+		if plan[i].Namespace != plan[j].Namespace {
+			return plan[i].Namespace < plan[j].Namespace
+		}
+
+		if plan[i].Name != plan[j].Name {
+			return plan[i].Name < plan[j].Name
+		}
+
+		return plan[i].Kind < plan[j].Kind
+	})
+
+	return plan, skipped, nil
+}
+
+func (r *SecretsSyncReconciler) plannedChange(action string, obj client.Object) internalv1alpha1.PlannedChange {
+	data := objectData(obj)
+	keys := make([]string, 0, len(data))
+	for key := range data {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	return internalv1alpha1.PlannedChange{
+		Action:    action,
+		Kind:      objectKind(obj),
+		Name:      obj.GetName(),
+		Namespace: obj.GetNamespace(),
+		Keys:      keys,
+		Hash:      dataHash(r.PlanHashKey, keys, data),
+	}
+}
+
+// dataHash returns a checksum of the object data keyed by the operator, so that the values
+// can not be guessed by hashing the candidates by anyone reading the status
+func dataHash(key []byte, keys []string, data map[string][]byte) string {
+	hash := hmac.New(sha256.New, key)
+	for _, key := range keys {
+		hash.Write([]byte(key))
+		hash.Write([]byte{0})
+		hash.Write(data[key])
+		hash.Write([]byte{0})
+	}
+
+	return "hmac-sha256:" + hex.EncodeToString(hash.Sum(nil))
+}
@@ -0,0 +1,182 @@
+/*
+Copyright 2025 Edenlab
+*/
+
+package controller
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"reflect"
+	"strings"
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestPlanChanges(t *testing.T) {
+	// unowned drops the owner labels, the object belongs to another tool
+	unowned := func(secret *v1.Secret) {
+		secret.Labels = map[string]string{"team": "platform"}
+	}
+
+	inNamespace := func(namespace string) func(secret *v1.Secret) {
+		return func(secret *v1.Secret) { secret.Namespace = namespace }
+	}
+
+	tests := []struct {
+		name           string
+		existing       []client.Object
+		desired        *v1.Secret
+		collectGarbage bool
+		wantActions    []string
+		wantSkipped    []string
+	}{
+		{
+			name:        "missing object is created",
+			desired:     newTestSecret(nil),
+			wantActions: []string{"Create default/app"},
+		},
+		{
+			name:     "object in sync",
+			existing: []client.Object{newTestSecret(nil)},
+			desired:  newTestSecret(nil),
+		},
+		{
+			name:        "changed data is updated",
+			existing:    []client.Object{newTestSecret(nil)},
+			desired:     newTestSecret(func(secret *v1.Secret) { secret.Data["password"] = []byte("rotated") }),
+			wantActions: []string{"Update default/app"},
+		},
+		{
+			name:        "changed type is recreated",
+			existing:    []client.Object{newTestSecret(nil)},
+			desired:     newTestSecret(func(secret *v1.Secret) { secret.Type = v1.SecretTypeBasicAuth }),
+			wantActions: []string{"Recreate default/app"},
+		},
+		{
+			name:        "unowned object in the namespace of the SecretsSync is adopted",
+			existing:    []client.Object{newTestSecret(unowned)},
+			desired:     newTestSecret(nil),
+			wantActions: []string{"Update default/app"},
+		},
+		{
+			name: "unowned object in another namespace is skipped",
+			existing: []client.Object{newTestSecret(func(secret *v1.Secret) {
+				unowned(secret)
+				inNamespace("apps")(secret)
+			})},
+			desired:     newTestSecret(inNamespace("apps")),
+			wantSkipped: []string{"Secret/apps/app: not owned"},
+		},
+		{
+			name: "object of another SecretsSync is never merged into",
+			existing: []client.Object{newTestSecret(func(secret *v1.Secret) {
+				secret.Labels[ownerName] = "other"
+			})},
+			desired: newTestSecret(func(secret *v1.Secret) {
+				markMerged(secret)
+			}),
+			wantSkipped: []string{"Secret/default/app: not owned"},
+		},
+		{
+			name: "orphans are deleted with the garbage collection",
+			existing: []client.Object{newTestSecret(func(secret *v1.Secret) {
+				secret.Name = "app-old"
+			})},
+			desired:        newTestSecret(nil),
+			collectGarbage: true,
+			wantActions:    []string{"Create default/app", "Delete default/app-old"},
+		},
+		{
+			name: "orphans are kept without the garbage collection",
+			existing: []client.Object{newTestSecret(func(secret *v1.Secret) {
+				secret.Name = "app-old"
+			})},
+			desired:     newTestSecret(nil),
+			wantActions: []string{"Create default/app"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			scheme := newTestScheme(t)
+			r := &SecretsSyncReconciler{
+				SystemInfo: &SystemInfo{
+					ctx: context.Background(),
+					req: ctrl.Request{NamespacedName: types.NamespacedName{Namespace: "default", Name: "app"}},
+				},
+				Scheme:      scheme,
+				Client:      fake.NewClientBuilder().WithScheme(scheme).WithObjects(tt.existing...).Build(),
+				PlanHashKey: []byte("test key"),
+			}
+
+			plan, skipped, err := r.planChanges(tt.collectGarbage, tt.desired)
+			if err != nil {
+				t.Fatalf("planChanges() error = %v", err)
+			}
+
+			var actions []string
+			for _, change := range plan {
+				actions = append(actions, change.Action+" "+change.Namespace+"/"+change.Name)
+			}
+
+			if !reflect.DeepEqual(actions, tt.wantActions) {
+				t.Errorf("planChanges() = %v, want %v", actions, tt.wantActions)
+			}
+
+			if !reflect.DeepEqual(skipped, tt.wantSkipped) {
+				t.Errorf("planChanges() skipped %v, want %v", skipped, tt.wantSkipped)
+			}
+		})
+	}
+}
+
+func TestDataHash(t *testing.T) {
+	keys, data := []string{"password"}, map[string][]byte{"password": []byte("secret")}
+
+	got := dataHash([]byte("first key"), keys, data)
+	if !strings.HasPrefix(got, "hmac-sha256:") {
+		t.Errorf("dataHash() = %s, want an hmac-sha256 hash", got)
+	}
+
+	if got != dataHash([]byte("first key"), keys, data) {
+		t.Error("hash of the same data changed")
+	}
+
+	if got == dataHash([]byte("second key"), keys, data) {
+		t.Error("hashes of different keys are equal")
+	}
+
+	// Without the key the hash of a guessed value can not be compared
+	guess := sha256.New()
+	guess.Write([]byte("password\x00secret\x00"))
+	if strings.TrimPrefix(got, "hmac-sha256:") == hex.EncodeToString(guess.Sum(nil)) {
+		t.Error("hash is not keyed")
+	}
+}
+
+func TestLoadPlanHashKey(t *testing.T) {
+	first, err := LoadPlanHashKey("")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	second, err := LoadPlanHashKey("")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(first) != planHashKeySize || string(first) == string(second) {
+		t.Error("generated keys are not random")
+	}
+
+	if _, err := LoadPlanHashKey(t.TempDir() + "/missing"); err == nil {
+		t.Error("LoadPlanHashKey() read a missing file")
+	}
+}
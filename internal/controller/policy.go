@@ -0,0 +1,127 @@
+/*
+Copyright 2025 Edenlab
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// allowedNamespacesAnnotation lists the names or glob patterns of the namespaces allowed to write secrets
+	// to the annotated namespace
+	allowedNamespacesAnnotation = "internal.edenlab.io/allowed-namespaces"
+
+	conditionDestinationsSkipped = "DestinationsSkipped"
+	reasonDestinationsSkipped    = "DestinationsSkipped"
+	reasonAllDestinationsSynced  = "AllDestinationsSynced"
+)
+
+// AccessPolicy decides whether the objects of a namespace may be copied to another namespace,
+// it is shared by all controllers. A copy within a namespace is always allowed. Across namespaces the side
+// which does not initiate the copy has to consent with the allowed-namespaces annotation: the destination
+// namespace for the copies pushed by a SecretsSync or the replicate-to annotation, the source secret for
+// the copies pulled by the reflect-from annotation. The manager allowlist only applies to the pushed copies.
+type AccessPolicy struct {
+	// DestinationNamespaces lists the names or glob patterns of the namespaces which accept copies from all namespaces
+	DestinationNamespaces []string
+}
+
+// NewAccessPolicy returns the policy with the comma separated allowlist of the destination namespaces
+func NewAccessPolicy(destinationNamespaces string) AccessPolicy {
+	return AccessPolicy{DestinationNamespaces: splitList(destinationNamespaces)}
+}
+
+// destinationAllowed reports whether the objects of the from namespace may be written to the namespace
+func (p AccessPolicy) destinationAllowed(ctx context.Context, c client.Reader, from, namespace string) (bool, error) {
+	if from == namespace || matchPatterns(p.DestinationNamespaces, namespace) {
+		return true, nil
+	}
+
+	dstNamespace := &v1.Namespace{}
+	if err := c.Get(ctx, types.NamespacedName{Name: namespace}, dstNamespace); err != nil {
+		return false, client.IgnoreNotFound(err)
+	}
+
+	return p.namespaceAllows(dstNamespace, from), nil
+}
+
+// namespaceAllows reports whether the destination namespace accepts the copies of the from namespace
+func (p AccessPolicy) namespaceAllows(dstNamespace *v1.Namespace, from string) bool {
+	return dstNamespace.Name == from || matchPatterns(p.DestinationNamespaces, dstNamespace.Name) ||
+		annotationAllows(dstNamespace, from)
+}
+
+// sourceAllowed reports whether the source may be copied to the namespace on request of the destination
+func (p AccessPolicy) sourceAllowed(src client.Object, namespace string) bool {
+	return src.GetNamespace() == namespace || annotationAllows(src, namespace)
+}
+
+// annotationAllows reports whether the allowed-namespaces annotation of the object lists the namespace
+func annotationAllows(obj client.Object, namespace string) bool {
+	return matchPatterns(splitList(obj.GetAnnotations()[allowedNamespacesAnnotation]), namespace)
+}
+
+// allowedDestinations drops the destinations in the namespaces which do not allow the SecretsSync namespace
+func (r *SecretsSyncReconciler) allowedDestinations(objects []client.Object) ([]client.Object, []string, error) {
+	var (
+		allowed []client.Object
+		skipped []string
+	)
+
+	for _, obj := range objects {
+		ok, err := r.AccessPolicy.destinationAllowed(r.ctx, r.Client, r.req.Namespace, obj.GetNamespace())
+		if err != nil {
+			return nil, nil, err
+		}
+
+		if !ok {
+			r.reqLogger.Info(fmt.Sprintf("Namespace %s does not allow secrets of namespace %s, %s %s skipped",
+				obj.GetNamespace(), r.req.Namespace, objectKind(obj), obj.GetName()))
+			skipped = append(skipped, fmt.Sprintf("%s: namespace not allowed", objectID(obj)))
+			continue
+		}
+
+		allowed = append(allowed, obj)
+	}
+
+	return allowed, skipped, nil
+}
+
+// updateSkippedCondition reports the destinations in not allowed namespaces and the existing objects
+// which are not owned by the SecretsSync
+func (r *SecretsSyncReconciler) updateSkippedCondition(skipped []string) {
+	conditions := append([]metav1.Condition(nil), r.secretsSync.Status.Conditions...)
+
+	switch {
+	case len(skipped) > 0:
+		sort.Strings(skipped)
+		meta.SetStatusCondition(&conditions, metav1.Condition{
+			Type:               conditionDestinationsSkipped,
+			Status:             metav1.ConditionTrue,
+			ObservedGeneration: r.secretsSync.Generation,
+			Reason:             reasonDestinationsSkipped,
+			Message:            strings.Join(skipped, "; "),
+		})
+	case meta.FindStatusCondition(conditions, conditionDestinationsSkipped) != nil:
+		meta.SetStatusCondition(&conditions, metav1.Condition{
+			Type:               conditionDestinationsSkipped,
+			Status:             metav1.ConditionFalse,
+			ObservedGeneration: r.secretsSync.Generation,
+			Reason:             reasonAllDestinationsSynced,
+			Message:            "All destinations are synced",
+		})
+	}
+
+	r.saveConditions(conditions)
+}
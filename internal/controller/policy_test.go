@@ -0,0 +1,161 @@
+/*
+Copyright 2025 Edenlab
+*/
+
+package controller
+
+import (
+	"context"
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestDestinationAllowed(t *testing.T) {
+	c := fake.NewClientBuilder().WithObjects(
+		&v1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "kube-system"}},
+		&v1.Namespace{ObjectMeta: metav1.ObjectMeta{
+			Name:        "team-a",
+			Annotations: map[string]string{allowedNamespacesAnnotation: "platform, ci-*"},
+		}},
+	).Build()
+	policy := NewAccessPolicy("preview-*")
+
+	tests := []struct {
+		name      string
+		from      string
+		namespace string
+		want      bool
+	}{
+		{name: "same namespace", from: "team-b", namespace: "team-b", want: true},
+		{name: "not annotated namespace", from: "platform", namespace: "kube-system"},
+		{name: "annotated namespace", from: "platform", namespace: "team-a", want: true},
+		{name: "annotated namespace pattern", from: "ci-runner", namespace: "team-a", want: true},
+		{name: "not listed namespace", from: "team-b", namespace: "team-a"},
+		{name: "manager allowlist", from: "team-b", namespace: "preview-42", want: true},
+		{name: "missing namespace", from: "platform", namespace: "absent"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := policy.destinationAllowed(context.Background(), c, tt.from, tt.namespace)
+			if err != nil {
+				t.Fatalf("destinationAllowed() error = %v", err)
+			}
+
+			if got != tt.want {
+				t.Errorf("destinationAllowed(%s, %s) = %v, want %v", tt.from, tt.namespace, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSyncObjectAdoption(t *testing.T) {
+	o := owner{kind: "SecretsSync", name: "app", namespace: "platform"}
+	unowned := func() *v1.Secret {
+		return &v1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "kube-system"},
+			Data:       map[string][]byte{"token": []byte("system")},
+			Type:       v1.SecretTypeOpaque,
+		}
+	}
+
+	desired := newTestSecret(func(secret *v1.Secret) {
+		secret.Namespace = "kube-system"
+		secret.Labels = o.labels()
+		secret.Annotations = nil
+	})
+
+	c := fake.NewClientBuilder().WithObjects(unowned()).Build()
+	if _, err := syncObject(context.Background(), c, o, desired.DeepCopy(), false); err != errNotOwned {
+		t.Errorf("syncObject() without adopt error = %v, want %v", err, errNotOwned)
+	}
+
+	merged := desired.DeepCopy()
+	markMerged(merged)
+	if _, err := syncObject(context.Background(), c, o, merged, false); err != errNotOwned {
+		t.Errorf("syncObject() of merged object without adopt error = %v, want %v", err, errNotOwned)
+	}
+
+	action, err := syncObject(context.Background(), c, o, desired.DeepCopy(), true)
+	if err != nil || action != planActionUpdate {
+		t.Errorf("syncObject() with adopt = %q, %v, want %q", action, err, planActionUpdate)
+	}
+}
+
+func TestSourceAllowed(t *testing.T) {
+	policy := NewAccessPolicy("*")
+	src := &v1.Secret{ObjectMeta: metav1.ObjectMeta{
+		Name:        "mongodb",
+		Namespace:   "mongodb",
+		Annotations: map[string]string{allowedNamespacesAnnotation: "app,team-*"},
+	}}
+
+	for namespace, want := range map[string]bool{"mongodb": true, "app": true, "team-a": true, "kube-system": false} {
+		// The manager allowlist does not allow the pulled copies
+		if got := policy.sourceAllowed(src, namespace); got != want {
+			t.Errorf("sourceAllowed(%s) = %v, want %v", namespace, got, want)
+		}
+	}
+}
+
+func TestReflectorSkipsSecretsWithData(t *testing.T) {
+	src := &v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "mongodb",
+			Namespace:   "mongodb",
+			Annotations: map[string]string{allowedNamespacesAnnotation: "app"},
+		},
+		Data: map[string][]byte{"password": []byte("secret")},
+	}
+
+	stub := func(name string, data map[string][]byte, reflected bool) *v1.Secret {
+		secret := &v1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        name,
+				Namespace:   "app",
+				Annotations: map[string]string{reflectFromAnnotation: "mongodb/mongodb"},
+			},
+			Data: data,
+		}
+
+		if reflected {
+			secret.Annotations[reflectedFromAnnotation] = "mongodb/mongodb"
+		}
+
+		return secret
+	}
+
+	tests := []struct {
+		name   string
+		dst    *v1.Secret
+		filled bool
+	}{
+		{name: "empty", dst: stub("empty", nil, false), filled: true},
+		{name: "previously reflected", dst: stub("reflected", map[string][]byte{"password": []byte("old")}, true), filled: true},
+		{name: "own data", dst: stub("own", map[string][]byte{"password": []byte("own")}, false)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := fake.NewClientBuilder().WithObjects(src.DeepCopy(), tt.dst).Build()
+			r := &SecretReflectorReconciler{Client: c}
+			if _, err := r.Reconcile(context.Background(), ctrl.Request{NamespacedName: client.ObjectKeyFromObject(tt.dst)}); err != nil {
+				t.Fatalf("Reconcile() error = %v", err)
+			}
+
+			got := &v1.Secret{}
+			if err := c.Get(context.Background(), client.ObjectKeyFromObject(tt.dst), got); err != nil {
+				t.Fatalf("Get() error = %v", err)
+			}
+
+			if filled := string(got.Data["password"]) == "secret"; filled != tt.filled {
+				t.Errorf("filled = %v, want %v, data %v", filled, tt.filled, got.Data)
+			}
+		})
+	}
+}
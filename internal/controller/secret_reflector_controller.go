@@ -0,0 +1,157 @@
+/*
+Copyright 2025 Edenlab
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+)
+
+const (
+	reflectFromAnnotation = "internal.edenlab.io/reflect-from"
+	// reflectedFromAnnotation marks the destinations filled by the reflector, so that only
+	// empty or previously reflected secrets are overwritten
+	reflectedFromAnnotation = "internal.edenlab.io/reflected-from"
+	reflectFromIndex        = "metadata.annotations.reflect-from"
+)
+
+// SecretReflectorReconciler fills the empty destination Secrets annotated with internal.edenlab.io/reflect-from
+// with the data of the referenced source Secret. The source has to allow the destination namespace
+// by the internal.edenlab.io/allowed-namespaces annotation.
+type SecretReflectorReconciler struct {
+	Scheme *runtime.Scheme
+	client.Client
+	// AccessPolicy allows the destination namespaces
+	AccessPolicy AccessPolicy
+}
+
+// Reconcile syncs the data of a single destination Secret
+func (r *SecretReflectorReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	reqLogger := log.FromContext(ctx)
+
+	dstSecret := &v1.Secret{}
+	if err := r.Client.Get(ctx, req.NamespacedName, dstSecret); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+
+	if !dstSecret.DeletionTimestamp.IsZero() || !hasReflectFrom(dstSecret) {
+		return ctrl.Result{}, nil
+	}
+
+	srcKey, err := parseReflectFrom(dstSecret.Annotations[reflectFromAnnotation])
+	if err != nil {
+		reqLogger.Error(err, fmt.Sprintf("Invalid %s annotation of secret %s", reflectFromAnnotation, req.NamespacedName))
+		return ctrl.Result{}, nil
+	}
+
+	srcSecret := &v1.Secret{}
+	if err := r.Client.Get(ctx, srcKey, srcSecret); err != nil {
+		if errors.IsNotFound(err) {
+			// The destination is reconciled again as soon as the source is created
+			reqLogger.Info(fmt.Sprintf("Source secret %s for secret %s not exist", srcKey, req.NamespacedName))
+			return ctrl.Result{}, nil
+		}
+
+		return ctrl.Result{}, err
+	}
+
+	if !r.AccessPolicy.sourceAllowed(srcSecret, dstSecret.Namespace) {
+		reqLogger.Info(fmt.Sprintf("Source secret %s does not allow reflection to namespace %s",
+			srcKey, dstSecret.Namespace))
+		return ctrl.Result{}, nil
+	}
+
+	// Secrets with data of their own are never overwritten
+	if len(dstSecret.Data) > 0 && dstSecret.Annotations[reflectedFromAnnotation] != srcKey.String() {
+		reqLogger.Info(fmt.Sprintf("Secret %s is not empty and was not reflected from %s, skipped",
+			req.NamespacedName, srcKey))
+		return ctrl.Result{}, nil
+	}
+
+	if dataEqual(dstSecret, srcSecret) && dstSecret.Annotations[reflectedFromAnnotation] == srcKey.String() {
+		return ctrl.Result{}, nil
+	}
+
+	dstSecret.Annotations[reflectedFromAnnotation] = srcKey.String()
+	dstSecret.Data = make(map[string][]byte, len(srcSecret.Data))
+	for key, val := range srcSecret.Data {
+		dstSecret.Data[key] = val
+	}
+
+	if err := r.Client.Update(ctx, dstSecret); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	reqLogger.Info(fmt.Sprintf("Secret %s has been reflected from %s", req.NamespacedName, srcKey))
+
+	return ctrl.Result{}, nil
+}
+
+// parseReflectFrom parses the namespace/name value of the reflect-from annotation
+func parseReflectFrom(value string) (types.NamespacedName, error) {
+	parts := strings.Split(strings.TrimSpace(value), "/")
+	if len(parts) != 2 || len(parts[0]) == 0 || len(parts[1]) == 0 {
+		return types.NamespacedName{}, fmt.Errorf("expected namespace/name, got %q", value)
+	}
+
+	return types.NamespacedName{Namespace: parts[0], Name: parts[1]}, nil
+}
+
+func hasReflectFrom(obj client.Object) bool {
+	_, ok := obj.GetAnnotations()[reflectFromAnnotation]
+	return ok
+}
+
+// reflections maps a source Secret to the destinations reflecting it
+func (r *SecretReflectorReconciler) reflections(obj client.Object) []reconcile.Request {
+	var requests []reconcile.Request
+
+	listSecrets := &v1.SecretList{}
+	if err := r.Client.List(context.Background(), listSecrets,
+		client.MatchingFields{reflectFromIndex: obj.GetNamespace() + "/" + obj.GetName()}); err != nil {
+		log.Log.Error(err, fmt.Sprintf("Unable to list reflections of secret %s/%s", obj.GetNamespace(), obj.GetName()))
+		return nil
+	}
+
+	for _, secret := range listSecrets.Items {
+		requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&secret)})
+	}
+
+	return requests
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *SecretReflectorReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	if err := mgr.GetFieldIndexer().IndexField(context.Background(), &v1.Secret{}, reflectFromIndex,
+		func(obj client.Object) []string {
+			if srcKey, err := parseReflectFrom(obj.GetAnnotations()[reflectFromAnnotation]); err == nil {
+				return []string{srcKey.String()}
+			}
+
+			return nil
+		}); err != nil {
+		return err
+	}
+
+	return ctrl.NewControllerManagedBy(mgr).
+		Named("secret-reflector").
+		For(&v1.Secret{}, builder.WithPredicates(predicate.NewPredicateFuncs(hasReflectFrom))).
+		Watches(&source.Kind{Type: &v1.Secret{}}, handler.EnqueueRequestsFromMapFunc(r.reflections)).
+		Complete(r)
+}
@@ -0,0 +1,265 @@
+/*
+Copyright 2025 Edenlab
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"strings"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	internalv1alpha1 "secrets-sync.operators.infra/api/v1alpha1"
+)
+
+const (
+	replicateToAnnotation = "internal.edenlab.io/replicate-to"
+	replicatorOwnerKind   = "Secret"
+)
+
+// SecretReplicatorReconciler copies the Secrets annotated with internal.edenlab.io/replicate-to
+// to the namespaces matching the comma separated list of names and glob patterns of the annotation
+type SecretReplicatorReconciler struct {
+	Scheme *runtime.Scheme
+	client.Client
+	// AccessPolicy allows the destination namespaces
+	AccessPolicy AccessPolicy
+	// MaxDestinations limits the number of replicas of a Secret, zero is unlimited
+	MaxDestinations int
+	// MaxDestinationBytes limits the size of the keys and values of a replica, defaults to 1MiB
+	MaxDestinationBytes int64
+	// MaxDestinationKeys limits the number of keys of a replica, zero is unlimited
+	MaxDestinationKeys int
+}
+
+// Reconcile syncs the copies of a single annotated source Secret
+func (r *SecretReplicatorReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	var newObjects []client.Object
+
+	reqLogger := log.FromContext(ctx)
+	srcOwner := owner{
+		kind:      replicatorOwnerKind,
+		name:      req.Name,
+		namespace: req.Namespace,
+	}
+
+	srcSecret := &v1.Secret{}
+	if err := r.Client.Get(ctx, req.NamespacedName, srcSecret); err != nil {
+		if !errors.IsNotFound(err) {
+			return ctrl.Result{}, err
+		}
+
+		// The copies of a removed source are collected below
+		srcSecret = nil
+	}
+
+	if srcSecret != nil && srcSecret.DeletionTimestamp.IsZero() {
+		namespaces, err := r.dstNamespaces(ctx, srcSecret)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+
+		if len(namespaces) > 0 {
+			newObjects, err = generateObjects(srcOwner, internalv1alpha1.SrcSecret{
+				SrcNamespace: srcSecret.Namespace,
+				DstSecrets:   []internalv1alpha1.DstSecret{{Namespaces: namespaces}},
+			}, srcSecret)
+			if err != nil {
+				return ctrl.Result{}, err
+			}
+		}
+
+		// The replicas are neither written nor collected while the source exceeds the limits,
+		// the previous ones are kept until the source or the annotation is changed
+		if err := r.checkLimits(newObjects); err != nil {
+			reqLogger.Error(err, fmt.Sprintf("Unable to replicate secret %s/%s", srcSecret.Namespace, srcSecret.Name))
+			return ctrl.Result{}, nil
+		}
+	}
+
+	orphans, err := orphanObjects(ctx, r.Client, srcOwner, newObjects...)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	for _, item := range orphans {
+		if err := r.Client.Delete(ctx, item); client.IgnoreNotFound(err) != nil {
+			return ctrl.Result{}, err
+		}
+
+		reqLogger.Info(fmt.Sprintf("Secret removed %s/%s", item.GetNamespace(), item.GetName()))
+	}
+
+	for _, obj := range newObjects {
+		action, err := syncObject(ctx, r.Client, srcOwner, obj, false)
+		if err != nil {
+			if err == errNotOwned {
+				reqLogger.Info(fmt.Sprintf("Secret %s/%s already exists and is not a replica, skipped",
+					obj.GetNamespace(), obj.GetName()))
+				continue
+			}
+
+			return ctrl.Result{}, err
+		}
+
+		if len(action) > 0 {
+			reqLogger.Info(fmt.Sprintf("Secret %s has been replicated to namespace %s", obj.GetName(), obj.GetNamespace()))
+		}
+	}
+
+	return ctrl.Result{}, nil
+}
+
+// checkLimits returns the first limit exceeded by the replicas
+func (r *SecretReplicatorReconciler) checkLimits(objects []client.Object) error {
+	l := managerLimits(r.MaxDestinations, r.MaxDestinationBytes, r.MaxDestinationKeys)
+
+	if err := checkDestinationsLimit(l, objects); err != nil {
+		return err
+	}
+
+	if _, errs := checkDestinationLimits(l, objects); len(errs) > 0 {
+		return errs[0]
+	}
+
+	return nil
+}
+
+// dstNamespaces returns the existing namespaces matching the replicate-to annotation of the source
+// which are allowed by the access policy
+func (r *SecretReplicatorReconciler) dstNamespaces(ctx context.Context, srcSecret *v1.Secret) ([]string, error) {
+	var namespaces []string
+
+	patterns := splitList(srcSecret.Annotations[replicateToAnnotation])
+	if len(patterns) == 0 {
+		return nil, nil
+	}
+
+	listNamespaces := &v1.NamespaceList{}
+	if err := r.Client.List(ctx, listNamespaces); err != nil {
+		return nil, err
+	}
+
+	for _, namespace := range listNamespaces.Items {
+		if namespace.Name == srcSecret.Namespace || !namespace.DeletionTimestamp.IsZero() {
+			continue
+		}
+
+		if !matchPatterns(patterns, namespace.Name) {
+			continue
+		}
+
+		if !r.AccessPolicy.namespaceAllows(&namespace, srcSecret.Namespace) {
+			log.FromContext(ctx).Info(fmt.Sprintf("Namespace %s does not allow secrets of namespace %s, skipped",
+				namespace.Name, srcSecret.Namespace))
+			continue
+		}
+
+		namespaces = append(namespaces, namespace.Name)
+	}
+
+	return namespaces, nil
+}
+
+// splitList splits a comma separated annotation value
+func splitList(value string) []string {
+	var items []string
+
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); len(item) > 0 {
+			items = append(items, item)
+		}
+	}
+
+	return items
+}
+
+// matchPatterns reports whether the name matches any of the names or glob patterns
+func matchPatterns(patterns []string, name string) bool {
+	for _, pattern := range patterns {
+		if ok, err := path.Match(pattern, name); err == nil && ok {
+			return true
+		}
+	}
+
+	return false
+}
+
+func hasReplicateTo(obj client.Object) bool {
+	_, ok := obj.GetAnnotations()[replicateToAnnotation]
+	return ok
+}
+
+// replicaSource maps a replica back to its source Secret
+func (r *SecretReplicatorReconciler) replicaSource(obj client.Object) []reconcile.Request {
+	objLabels := obj.GetLabels()
+	if objLabels[ownerKind] != replicatorOwnerKind {
+		return nil
+	}
+
+	return []reconcile.Request{{NamespacedName: types.NamespacedName{
+		Name:      objLabels[ownerName],
+		Namespace: objLabels[ownerNamespace],
+	}}}
+}
+
+// annotatedSecrets enqueues all annotated Secrets, so that new namespaces receive their replicas
+func (r *SecretReplicatorReconciler) annotatedSecrets(_ client.Object) []reconcile.Request {
+	var requests []reconcile.Request
+
+	listSecrets := &v1.SecretList{}
+	if err := r.Client.List(context.Background(), listSecrets); err != nil {
+		log.Log.Error(err, "Unable to list secrets for replication")
+		return nil
+	}
+
+	for _, secret := range listSecrets.Items {
+		if hasReplicateTo(&secret) {
+			requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&secret)})
+		}
+	}
+
+	return requests
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *SecretReplicatorReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		Named("secret-replicator").
+		For(&v1.Secret{}, builder.WithPredicates(predicate.Funcs{
+			CreateFunc: func(e event.CreateEvent) bool { return hasReplicateTo(e.Object) },
+			// Removal of the annotation has to be handled as well to collect the replicas
+			UpdateFunc: func(e event.UpdateEvent) bool {
+				return hasReplicateTo(e.ObjectOld) || hasReplicateTo(e.ObjectNew)
+			},
+			DeleteFunc:  func(e event.DeleteEvent) bool { return hasReplicateTo(e.Object) },
+			GenericFunc: func(e event.GenericEvent) bool { return hasReplicateTo(e.Object) },
+		})).
+		Watches(&source.Kind{Type: &v1.Secret{}}, handler.EnqueueRequestsFromMapFunc(r.replicaSource)).
+		Watches(&source.Kind{Type: &v1.Namespace{}}, handler.EnqueueRequestsFromMapFunc(r.annotatedSecrets),
+			builder.WithPredicates(predicate.Funcs{
+				// A namespace starting to allow the copies receives them
+				UpdateFunc: func(e event.UpdateEvent) bool {
+					return e.ObjectOld.GetAnnotations()[allowedNamespacesAnnotation] !=
+						e.ObjectNew.GetAnnotations()[allowedNamespacesAnnotation]
+				},
+				DeleteFunc:  func(event.DeleteEvent) bool { return false },
+				GenericFunc: func(event.GenericEvent) bool { return false },
+			})).
+		Complete(r)
+}
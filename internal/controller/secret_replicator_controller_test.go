@@ -0,0 +1,310 @@
+/*
+Copyright 2025 Edenlab
+*/
+
+package controller
+
+import (
+	"context"
+	"reflect"
+	"sort"
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func newTestReplicatorSource(replicateTo string) *v1.Secret {
+	return &v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "app",
+			Namespace:   "platform",
+			Annotations: map[string]string{replicateToAnnotation: replicateTo},
+		},
+		Data: map[string][]byte{"password": []byte("secret")},
+		Type: v1.SecretTypeOpaque,
+	}
+}
+
+func newTestNamespace(name, allowed string) *v1.Namespace {
+	namespace := &v1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: name}}
+	if len(allowed) > 0 {
+		namespace.Annotations = map[string]string{allowedNamespacesAnnotation: allowed}
+	}
+
+	return namespace
+}
+
+func reconcileTestReplicator(t *testing.T, r *SecretReplicatorReconciler) {
+	t.Helper()
+
+	req := ctrl.Request{NamespacedName: client.ObjectKey{Name: "app", Namespace: "platform"}}
+	if _, err := r.Reconcile(context.Background(), req); err != nil {
+		t.Fatalf("Reconcile() error = %v", err)
+	}
+}
+
+// replicaData returns the data of the replicas by namespace
+func replicaData(t *testing.T, c client.Client) map[string]string {
+	t.Helper()
+
+	replicas := &v1.SecretList{}
+	if err := c.List(context.Background(), replicas, client.MatchingLabels{ownerKind: replicatorOwnerKind}); err != nil {
+		t.Fatal(err)
+	}
+
+	data := make(map[string]string, len(replicas.Items))
+	for _, replica := range replicas.Items {
+		data[replica.Namespace] = string(replica.Data["password"])
+	}
+
+	return data
+}
+
+func namespacesOf(data map[string]string) []string {
+	var namespaces []string
+	for namespace := range data {
+		namespaces = append(namespaces, namespace)
+	}
+
+	sort.Strings(namespaces)
+
+	return namespaces
+}
+
+func TestReplicatorFanOut(t *testing.T) {
+	tests := []struct {
+		name        string
+		replicateTo string
+		policy      AccessPolicy
+		want        []string
+	}{
+		{name: "name", replicateTo: "team-a", want: []string{"team-a"}},
+		{name: "pattern", replicateTo: "team-*", want: []string{"team-a", "team-b"}},
+		{name: "names and patterns", replicateTo: "team-a, ops-*", want: []string{"ops-1", "team-a"}},
+		{name: "no match", replicateTo: "absent-*"},
+		{
+			// The source namespace is never a destination
+			name:        "all namespaces",
+			replicateTo: "*",
+			want:        []string{"ops-1", "team-a", "team-b"},
+		},
+		{
+			name:        "manager allowlist",
+			replicateTo: "*",
+			policy:      NewAccessPolicy("team-c"),
+			want:        []string{"ops-1", "team-a", "team-b", "team-c"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &SecretReplicatorReconciler{
+				AccessPolicy: tt.policy,
+				Client: fake.NewClientBuilder().WithObjects(
+					newTestNamespace("platform", ""),
+					newTestNamespace("team-a", "platform"),
+					newTestNamespace("team-b", "plat*"),
+					newTestNamespace("ops-1", "ci, platform"),
+					// The namespaces without consent receive no replicas
+					newTestNamespace("team-c", ""),
+					newTestNamespace("team-d", "ci"),
+					newTestReplicatorSource(tt.replicateTo),
+				).Build(),
+			}
+
+			reconcileTestReplicator(t, r)
+
+			got := replicaData(t, r.Client)
+			if namespaces := namespacesOf(got); !reflect.DeepEqual(namespaces, tt.want) {
+				t.Errorf("got replicas in %v, want %v", namespaces, tt.want)
+			}
+
+			for namespace, password := range got {
+				if password != "secret" {
+					t.Errorf("replica in %s has password %q, want %q", namespace, password, "secret")
+				}
+			}
+		})
+	}
+}
+
+func TestReplicatorSkipsForeignSecrets(t *testing.T) {
+	foreign := &v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "team-b"},
+		Data:       map[string][]byte{"password": []byte("foreign")},
+		Type:       v1.SecretTypeOpaque,
+	}
+
+	r := &SecretReplicatorReconciler{
+		Client: fake.NewClientBuilder().WithObjects(
+			newTestNamespace("platform", ""),
+			newTestNamespace("team-a", "platform"),
+			newTestNamespace("team-b", "platform"),
+			newTestReplicatorSource("team-*"),
+			foreign,
+		).Build(),
+	}
+
+	reconcileTestReplicator(t, r)
+
+	if got := namespacesOf(replicaData(t, r.Client)); !reflect.DeepEqual(got, []string{"team-a"}) {
+		t.Errorf("got replicas in %v, want [team-a]", got)
+	}
+
+	got := &v1.Secret{}
+	if err := r.Client.Get(context.Background(), client.ObjectKeyFromObject(foreign), got); err != nil {
+		t.Fatal(err)
+	}
+
+	if string(got.Data["password"]) != "foreign" {
+		t.Errorf("foreign secret has password %q, want %q", got.Data["password"], "foreign")
+	}
+}
+
+func TestReplicatorCollectsReplicas(t *testing.T) {
+	tests := []struct {
+		name   string
+		update func(ctx context.Context, c client.Client, src *v1.Secret) error
+		want   []string
+	}{
+		{
+			name: "annotation removed",
+			update: func(ctx context.Context, c client.Client, src *v1.Secret) error {
+				delete(src.Annotations, replicateToAnnotation)
+				return c.Update(ctx, src)
+			},
+		},
+		{
+			name: "annotation narrowed",
+			update: func(ctx context.Context, c client.Client, src *v1.Secret) error {
+				src.Annotations[replicateToAnnotation] = "team-b"
+				return c.Update(ctx, src)
+			},
+			want: []string{"team-b"},
+		},
+		{
+			name: "source deleted",
+			update: func(ctx context.Context, c client.Client, src *v1.Secret) error {
+				return c.Delete(ctx, src)
+			},
+		},
+		{
+			name: "consent withdrawn",
+			update: func(ctx context.Context, c client.Client, _ *v1.Secret) error {
+				return c.Update(ctx, newTestNamespace("team-a", "ci"))
+			},
+			want: []string{"team-b"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			r := &SecretReplicatorReconciler{
+				Client: fake.NewClientBuilder().WithObjects(
+					newTestNamespace("platform", ""),
+					newTestNamespace("team-a", "platform"),
+					newTestNamespace("team-b", "platform"),
+					newTestReplicatorSource("team-*"),
+				).Build(),
+			}
+
+			reconcileTestReplicator(t, r)
+
+			if got := namespacesOf(replicaData(t, r.Client)); !reflect.DeepEqual(got, []string{"team-a", "team-b"}) {
+				t.Fatalf("got replicas in %v, want [team-a team-b]", got)
+			}
+
+			src := &v1.Secret{}
+			if err := r.Client.Get(ctx, client.ObjectKey{Name: "app", Namespace: "platform"}, src); err != nil {
+				t.Fatal(err)
+			}
+
+			if err := tt.update(ctx, r.Client, src); err != nil {
+				t.Fatal(err)
+			}
+
+			reconcileTestReplicator(t, r)
+
+			got := namespacesOf(replicaData(t, r.Client))
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got replicas in %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReplicatorKeepsReplicasOverLimits(t *testing.T) {
+	tests := []struct {
+		name   string
+		update func(src *v1.Secret)
+	}{
+		{
+			name: "too many replicas",
+			update: func(src *v1.Secret) {
+				src.Annotations[replicateToAnnotation] = "team-*, ops-*"
+				src.Data["password"] = []byte("rotated")
+			},
+		},
+		{
+			name: "too many keys",
+			update: func(src *v1.Secret) {
+				src.Data["password"] = []byte("rotated")
+				src.Data["token"] = []byte("token")
+				src.Data["username"] = []byte("admin")
+			},
+		},
+		{
+			name: "too many bytes",
+			update: func(src *v1.Secret) {
+				src.Data["password"] = make([]byte, 64)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			r := &SecretReplicatorReconciler{
+				MaxDestinations:     2,
+				MaxDestinationKeys:  2,
+				MaxDestinationBytes: 32,
+				Client: fake.NewClientBuilder().WithObjects(
+					newTestNamespace("platform", ""),
+					newTestNamespace("team-a", "platform"),
+					newTestNamespace("team-b", "platform"),
+					newTestNamespace("ops-1", "platform"),
+					newTestReplicatorSource("team-*"),
+				).Build(),
+			}
+
+			reconcileTestReplicator(t, r)
+
+			want := map[string]string{"team-a": "secret", "team-b": "secret"}
+			if got := replicaData(t, r.Client); !reflect.DeepEqual(got, want) {
+				t.Fatalf("got replicas %v, want %v", got, want)
+			}
+
+			src := &v1.Secret{}
+			if err := r.Client.Get(ctx, client.ObjectKey{Name: "app", Namespace: "platform"}, src); err != nil {
+				t.Fatal(err)
+			}
+
+			tt.update(src)
+			if err := r.Client.Update(ctx, src); err != nil {
+				t.Fatal(err)
+			}
+
+			reconcileTestReplicator(t, r)
+
+			// The previous replicas are neither updated nor collected
+			if got := replicaData(t, r.Client); !reflect.DeepEqual(got, want) {
+				t.Errorf("got replicas %v, want %v", got, want)
+			}
+		})
+	}
+}
@@ -6,29 +6,52 @@ package controller
 
 import (
 	"context"
+	goerrors "errors"
 	"fmt"
 	"github.com/go-logr/logr"
-	"k8s.io/apimachinery/pkg/labels"
 	"reflect"
+	"sort"
+	"strings"
 	"time"
 
 	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
 	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/client-go/util/workqueue"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
 
 	internalv1alpha1 "secrets-sync.operators.infra/api/v1alpha1"
 )
 
 const (
-	frequency = time.Second * 3
-	ownerKind = "internal.edenlab.io/owner-kind"
-	ownerName = "internal.edenlab.io/owner-name"
+	ownerKind      = "internal.edenlab.io/owner-kind"
+	ownerName      = "internal.edenlab.io/owner-name"
+	ownerNamespace = "internal.edenlab.io/owner-namespace"
+	finalizerName  = "internal.edenlab.io/finalizer"
+
+	sourceNamespaceIndex = "spec.secrets.srcNamespace"
+	sourceIndex          = "spec.secrets"
+
+	conditionRequiredKeysMissing = "RequiredKeysMissing"
+	reasonMissingKeys            = "MissingKeys"
+	reasonKeysPresent            = "KeysPresent"
 )
 
 var (
@@ -43,6 +66,28 @@ type SecretsSyncReconciler struct {
 	*SystemInfo
 	Scheme *runtime.Scheme
 	client.Client
+	// RefreshInterval is used for the SecretsSync objects without spec.refreshInterval
+	RefreshInterval time.Duration
+	// MaxBackoff limits the requeue delay of the failing SecretsSync objects
+	MaxBackoff time.Duration
+	// DryRun enables the plan mode for all SecretsSync objects
+	DryRun bool
+	// CertificateExpiryWindow is used for the SecretsSync objects without spec.certificateExpiryWindow
+	CertificateExpiryWindow time.Duration
+	// Recorder emits the Warning events of the expiring certificates
+	Recorder record.EventRecorder
+	// AccessPolicy allows the destinations outside the namespace of the SecretsSync
+	AccessPolicy AccessPolicy
+	// MaxDestinations limits the number of destinations of a SecretsSync, zero is unlimited
+	MaxDestinations int
+	// MaxDestinationBytes limits the size of the keys and values of a destination, defaults to 1MiB
+	MaxDestinationBytes int64
+	// MaxDestinationKeys limits the number of keys of a destination, zero is unlimited
+	MaxDestinationKeys int
+	// PlanHashKey keys the hashes of the data in the dry-run plans
+	PlanHashKey []byte
+
+	backoff failureBackoff
 }
 
 type SystemInfo struct {
@@ -55,6 +100,10 @@ type SystemInfo struct {
 //+kubebuilder:rbac:groups=internal.edenlab.io,resources=secretssyncs,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=internal.edenlab.io,resources=secretssyncs/status,verbs=get;update;patch
 //+kubebuilder:rbac:groups=internal.edenlab.io,resources=secretssyncs/finalizers,verbs=update
+//+kubebuilder:rbac:groups="",resources=secrets;configmaps,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups="",resources=namespaces,verbs=get;list;watch
+//+kubebuilder:rbac:groups="",resources=serviceaccounts,verbs=get;list;watch;update;patch
+//+kubebuilder:rbac:groups="",resources=events,verbs=create;patch
 
 // Reconcile is part of the main kubernetes reconciliation loop which aims to
 // move the current state of the cluster closer to the desired state.
@@ -66,7 +115,13 @@ type SystemInfo struct {
 // For more details, check Reconcile and its Result here:
 // - https://pkg.go.dev/sigs.k8s.io/controller-runtime@v0.14.4/pkg/reconcile
 func (r *SecretsSyncReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
-	var newSecrets []*v1.Secret
+	var (
+		newObjects         []client.Object
+		missingSources     bool
+		generateErrs       []error
+		immutableConflicts []string
+		skipped            []string
+	)
 
 	r.ctx = ctx
 	r.req = req
@@ -76,100 +131,389 @@ func (r *SecretsSyncReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 	if err := r.Client.Get(r.ctx, req.NamespacedName, r.secretsSync); err != nil {
 		if errors.IsNotFound(err) {
 			r.reqLogger.Error(nil, fmt.Sprintf("Can not find CRD by name: %s", r.req.Name))
+			r.backoff.reset(req.NamespacedName)
+			r.deleteCertificateMetrics()
 			return ctrl.Result{}, nil
 		}
 
 		return ctrl.Result{}, err
 	}
 
+	if !r.secretsSync.DeletionTimestamp.IsZero() {
+		r.deleteCertificateMetrics()
+		return ctrl.Result{}, r.finalize()
+	}
+
+	// The specs rejected by the validating webhook are not synced when the webhook is disabled,
+	// the previous destinations are kept until the spec is fixed
+	if errs := internalv1alpha1.ValidateSpec(&r.secretsSync.Spec, field.NewPath("spec")); len(errs) > 0 {
+		invalidErr := errs.ToAggregate()
+		r.reqLogger.Error(invalidErr, "Invalid spec, the destinations are not synced")
+		if r.secretsSync.Status.Phase != "Failed" || r.secretsSync.Status.Error != invalidErr.Error() {
+			r.updateStatusCRD("Failed", invalidErr.Error(), r.secretsSync.Status.Count)
+		}
+
+		r.backoff.reset(req.NamespacedName)
+
+		return ctrl.Result{}, nil
+	}
+
+	if r.needsFinalizer() && !controllerutil.ContainsFinalizer(r.secretsSync, finalizerName) {
+		// Owner references can not point to another namespace, such objects are removed by the finalizer.
+		// The image pull secrets are removed from the service accounts by the finalizer as well.
+		controllerutil.AddFinalizer(r.secretsSync, finalizerName)
+		if err := r.Client.Update(r.ctx, r.secretsSync); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
 	for srcSecretName, val := range r.secretsSync.Spec.Secrets {
 		if err := r.Client.Get(r.ctx, types.NamespacedName{Name: val.SrcNamespace}, &v1.Namespace{}); err != nil {
 			if errors.IsNotFound(err) {
-				if r.secretsSync.Status.Phase != "PartiallySynced" && r.secretsSync.Status.Count > 0 {
-					r.reqLogger.Error(err, fmt.Sprintf("Source namespace %s for secret %s not exist",
-						val.SrcNamespace, srcSecretName))
-					r.updateStatusCRD("PartiallySynced",
-						fmt.Sprintf("Source namespace %s for secret %s not exist",
-							val.SrcNamespace, srcSecretName), r.secretsSync.Status.Count)
-				}
-
-				if r.secretsSync.Status.Phase != "NotSynced" && r.secretsSync.Status.Count == 0 {
-					r.reqLogger.Error(err, fmt.Sprintf("Source namespace %s for secret %s not exist",
-						val.SrcNamespace, srcSecretName))
-					r.updateStatusCRD("NotSynced",
-						fmt.Sprintf("Source namespace %s for secret %s not exist",
-							val.SrcNamespace, srcSecretName), 0)
+				if val.Optional {
+					r.reqLogger.Info(fmt.Sprintf("Optional source namespace %s not exist", val.SrcNamespace))
+					continue
 				}
 
+				r.reportMissingSource(err, val, srcSecretName)
+				missingSources = true
 				continue
 			} else {
 				return ctrl.Result{}, err
 			}
 		} else {
-			srcSecret := &v1.Secret{}
-			if err := r.Client.Get(r.ctx, types.NamespacedName{Name: srcSecretName, Namespace: val.SrcNamespace}, srcSecret); err != nil {
+			srcSecret, err := r.getSource(srcSecretName, val)
+			if err != nil {
 				if errors.IsNotFound(err) {
-					if r.secretsSync.Status.Phase != "PartiallySynced" && r.secretsSync.Status.Count > 0 {
-						r.reqLogger.Error(err, fmt.Sprintf("Source namespace %s for secret %s not exist",
-							val.SrcNamespace, srcSecretName))
-						r.updateStatusCRD("PartiallySynced",
-							fmt.Sprintf("Source namespace %s for secret %s not exist",
-								val.SrcNamespace, srcSecretName), r.secretsSync.Status.Count)
-					}
-
-					if r.secretsSync.Status.Phase != "NotSynced" && r.secretsSync.Status.Count == 0 {
-						r.reqLogger.Error(err, fmt.Sprintf("Source namespace %s for secret %s not exist",
-							val.SrcNamespace, srcSecretName))
-						r.updateStatusCRD("NotSynced",
-							fmt.Sprintf("Source namespace %s for secret %s not exist",
-								val.SrcNamespace, srcSecretName), 0)
+					if val.Optional {
+						r.reqLogger.Info(fmt.Sprintf("Optional source %s/%s not exist", val.SrcNamespace, srcSecretName))
+						continue
 					}
 
+					r.reportMissingSource(err, val, srcSecretName)
+					missingSources = true
 					continue
 				} else {
 					return ctrl.Result{}, err
 				}
 			} else {
-				newSecrets = append(newSecrets, r.GenerateSecrets(val.DstSecrets, srcSecret)...)
+				objects, err := r.GenerateSecrets(val, srcSecret)
+				if err != nil {
+					r.reqLogger.Error(err, fmt.Sprintf("Unable to generate destinations of %s", srcSecretName))
+					for _, destinationErr := range utilerrors.Flatten(utilerrors.NewAggregate([]error{err})).Errors() {
+						generateErrs = append(generateErrs,
+							fmt.Errorf("source %s/%s: %w", val.SrcNamespace, srcSecretName, destinationErr))
+					}
+				}
+
+				newObjects = append(newObjects, objects...)
 			}
 		}
 	}
 
-	if err := r.garbageCollector(newSecrets...); err != nil {
+	newObjects, err := dropCollisions(newObjects)
+	if err != nil {
+		r.reqLogger.Error(err, "Unable to sync colliding destinations")
+		generateErrs = append(generateErrs, err)
+	}
+
+	// None of the destinations is written when there are too many of them
+	if err := checkDestinationsLimit(r.limits(), newObjects); err != nil {
+		r.reqLogger.Error(err, "Unable to sync destinations")
+		generateErrs = append(generateErrs, err)
+		newObjects = nil
+	}
+
+	// The objects in other namespaces are only written with the consent of the destination namespace
+	newObjects, skipped, err = r.allowedDestinations(newObjects)
+	if err != nil {
 		return ctrl.Result{}, err
 	}
 
-	defSecret := &v1.Secret{}
-	for _, secret := range newSecrets {
-		if err := r.Client.Get(r.ctx, types.NamespacedName{Name: secret.Name, Namespace: secret.Namespace}, defSecret); err != nil {
-			if errors.IsNotFound(err) {
-				if err := r.CreateSecret(secret); err != nil {
-					return ctrl.Result{}, err
-				}
+	generateErr := utilerrors.NewAggregate(generateErrs)
+	generateMessage := ""
+	if generateErr != nil {
+		generateMessage = generateErr.Error()
+	}
 
-				r.reqLogger.Info(fmt.Sprintf("New secret %s has been synced for namespace %s", secret.Name, secret.Namespace))
-				r.updateStatusCRD("Synced", "", len(newSecrets))
-				continue
-			} else {
+	r.updateRequiredKeysCondition(generateErrs)
+	r.updateLimitCondition(generateErrs)
+
+	if r.dryRun() {
+		plan, notOwned, err := r.planChanges(generateErr == nil, newObjects...)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+
+		skipped = append(skipped, notOwned...)
+
+		if r.secretsSync.Status.Phase != "Planned" || r.secretsSync.Status.Error != generateMessage ||
+			!reflect.DeepEqual(r.secretsSync.Status.Plan, plan) {
+			r.reqLogger.Info(fmt.Sprintf("Dry-run plan has %d changes", len(plan)))
+			r.secretsSync.Status.Plan = plan
+			r.updateStatusCRD("Planned", generateMessage, r.secretsSync.Status.Count)
+		}
+
+		r.updateSkippedCondition(skipped)
+
+		return r.requeue(missingSources || generateErr != nil), nil
+	}
+
+	if len(r.secretsSync.Status.Plan) > 0 || r.secretsSync.Status.Phase == "Planned" {
+		r.secretsSync.Status.Plan = nil
+		if r.secretsSync.Status.Phase == "Planned" {
+			r.updateStatusCRD("Synced", "", len(newObjects))
+		} else {
+			r.updateStatusCRD(r.secretsSync.Status.Phase, r.secretsSync.Status.Error, r.secretsSync.Status.Count)
+		}
+	}
+
+	// The previous destinations of the failed sources are kept until they are generated successfully
+	if generateErr == nil {
+		if err := r.garbageCollector(newObjects...); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
+	// The certificates are only reported for the objects which were synced, not the skipped ones
+	var synced []client.Object
+	for _, obj := range newObjects {
+		// Used to ensure that the object will be deleted when the custom resource object is removed,
+		// the objects in other namespaces are removed by the finalizer
+		// The merged objects are shared with other tools and are never removed with the custom resource
+		if obj.GetNamespace() == r.req.Namespace && !isMerged(obj) {
+			if err := ctrl.SetControllerReference(r.secretsSync, obj, r.Scheme); err != nil {
 				return ctrl.Result{}, err
 			}
 		}
 
-		if !reflect.DeepEqual(defSecret.Data, secret.Data) {
-			if err := r.Client.Delete(r.ctx, secret); err != nil {
-				return ctrl.Result{}, err
+		// The service accounts lose the secret when the annotation is removed by the sync
+		hadImagePull, err := hasImagePullServiceAccounts(r.ctx, r.Client, obj)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+
+		// Unowned objects are only adopted in the namespace of the SecretsSync
+		action, err := syncObject(r.ctx, r.Client, r.owner(), obj, obj.GetNamespace() == r.req.Namespace)
+		if err != nil {
+			if err == errNotOwned {
+				r.reqLogger.Info(fmt.Sprintf("%s %s/%s already exists and is not managed by the SecretsSync, skipped",
+					objectKind(obj), obj.GetNamespace(), obj.GetName()))
+				skipped = append(skipped, fmt.Sprintf("%s: not owned", objectID(obj)))
+				continue
 			}
 
-			if err := r.CreateSecret(secret); err != nil {
-				return ctrl.Result{}, err
+			if goerrors.Is(err, errImmutable) {
+				r.reqLogger.Info(fmt.Sprintf("%s %s/%s is immutable and differs from the destination, skipped",
+					objectKind(obj), obj.GetNamespace(), obj.GetName()))
+				immutableConflicts = append(immutableConflicts, objectID(obj))
+				continue
+			}
+
+			r.updateStatusCRD("Failed", err.Error(), 0)
+			return ctrl.Result{}, err
+		}
+
+		synced = append(synced, obj)
+
+		switch action {
+		case planActionCreate:
+			r.reqLogger.Info(fmt.Sprintf("New %s %s has been synced for namespace %s",
+				objectKind(obj), obj.GetName(), obj.GetNamespace()))
+			r.updateStatusCRD("Synced", "", len(newObjects))
+		case planActionUpdate:
+			r.reqLogger.Info(fmt.Sprintf("%s %s has been synced due to a difference in the data or metadata",
+				objectKind(obj), obj.GetName()))
+			r.updateStatusCRD("Synced", "", len(newObjects))
+		case planActionRecreate:
+			r.reqLogger.Info(fmt.Sprintf("%s %s has been recreated due to a difference in the type or immutable data",
+				objectKind(obj), obj.GetName()))
+			r.updateStatusCRD("Synced", "", len(newObjects))
+		}
+
+		// Service accounts created after the secret receive it on the next sync
+		if err := syncImagePullSecret(r.ctx, r.Client, obj, hadImagePull); err != nil {
+			r.updateStatusCRD("Failed", err.Error(), 0)
+			return ctrl.Result{}, err
+		}
+	}
+
+	r.updateImmutableCondition(immutableConflicts)
+	r.updateSkippedCondition(skipped)
+	r.updateCertificates(synced...)
+
+	if generateErr != nil && (r.secretsSync.Status.Phase != "Failed" || r.secretsSync.Status.Error != generateMessage) {
+		r.updateStatusCRD("Failed", generateMessage, len(newObjects))
+	}
+
+	return r.requeue(missingSources || generateErr != nil), nil
+}
+
+// updateRequiredKeysCondition sets the RequiredKeysMissing condition when any destination declares required keys
+func (r *SecretsSyncReconciler) updateRequiredKeysCondition(generateErrs []error) {
+	var missing []string
+
+	for _, err := range generateErrs {
+		var missingErr *missingKeysError
+		if goerrors.As(err, &missingErr) {
+			missing = append(missing, err.Error())
+		}
+	}
+
+	conditions := append([]metav1.Condition(nil), r.secretsSync.Status.Conditions...)
+	if !r.hasRequiredKeys() {
+		meta.RemoveStatusCondition(&conditions, conditionRequiredKeysMissing)
+	} else if len(missing) > 0 {
+		sort.Strings(missing)
+		meta.SetStatusCondition(&conditions, metav1.Condition{
+			Type:               conditionRequiredKeysMissing,
+			Status:             metav1.ConditionTrue,
+			ObservedGeneration: r.secretsSync.Generation,
+			Reason:             reasonMissingKeys,
+			Message:            strings.Join(missing, "; "),
+		})
+	} else {
+		meta.SetStatusCondition(&conditions, metav1.Condition{
+			Type:               conditionRequiredKeysMissing,
+			Status:             metav1.ConditionFalse,
+			ObservedGeneration: r.secretsSync.Generation,
+			Reason:             reasonKeysPresent,
+			Message:            "All required keys are present",
+		})
+	}
+
+	r.saveConditions(conditions)
+}
+
+// saveConditions updates the status when the conditions differ from the current ones
+func (r *SecretsSyncReconciler) saveConditions(conditions []metav1.Condition) {
+	if equality.Semantic.DeepEqual(conditions, r.secretsSync.Status.Conditions) {
+		return
+	}
+
+	r.secretsSync.Status.Conditions = conditions
+	r.updateStatusCRD(r.secretsSync.Status.Phase, r.secretsSync.Status.Error, r.secretsSync.Status.Count)
+}
+
+func (r *SecretsSyncReconciler) hasRequiredKeys() bool {
+	for _, val := range r.secretsSync.Spec.Secrets {
+		for _, dstSecret := range val.DstSecrets {
+			if len(dstSecret.RequiredKeys) > 0 {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// finalize removes the objects owned by the deleted SecretsSync in all namespaces
+func (r *SecretsSyncReconciler) finalize() error {
+	if !controllerutil.ContainsFinalizer(r.secretsSync, finalizerName) {
+		return nil
+	}
+
+	orphans, err := orphanObjects(r.ctx, r.Client, r.owner())
+	if err != nil {
+		return err
+	}
+
+	for _, item := range orphans {
+		if err := removeImagePullSecret(r.ctx, r.Client, item); err != nil {
+			return err
+		}
+
+		if err := removeObject(r.ctx, r.Client, item); client.IgnoreNotFound(err) != nil {
+			return err
+		}
+
+		r.reqLogger.Info(fmt.Sprintf("%s removed %s/%s", objectKind(item), item.GetNamespace(), item.GetName()))
+	}
+
+	controllerutil.RemoveFinalizer(r.secretsSync, finalizerName)
+
+	return r.Client.Update(r.ctx, r.secretsSync)
+}
+
+// needsFinalizer reports whether any destination is created outside the namespace of the SecretsSync
+// or is added to the image pull secrets of service accounts or is merged into an object shared with other tools
+func (r *SecretsSyncReconciler) needsFinalizer() bool {
+	for _, val := range r.secretsSync.Spec.Secrets {
+		for _, dstSecret := range val.DstSecrets {
+			if len(dstSecret.ImagePullServiceAccounts) > 0 || dstSecret.Mode == modeMerge {
+				return true
 			}
 
-			r.reqLogger.Info(fmt.Sprintf("Secret %s has been synced due to a difference in the data field", secret.Name))
-			r.updateStatusCRD("Synced", "", len(newSecrets))
+			for _, namespace := range dstSecret.Namespaces {
+				if namespace != r.req.Namespace {
+					return true
+				}
+			}
 		}
 	}
 
-	return ctrl.Result{RequeueAfter: frequency}, nil
+	return false
+}
+
+func (r *SecretsSyncReconciler) requeue(failed bool) ctrl.Result {
+	if failed {
+		delay := r.backoff.next(r.req.NamespacedName, r.refreshInterval(), r.maxBackoff())
+		r.reqLogger.Info(fmt.Sprintf("Some sources are missing or failed, next sync in %s", delay))
+		return ctrl.Result{RequeueAfter: delay}
+	}
+
+	r.backoff.reset(r.req.NamespacedName)
+
+	return ctrl.Result{RequeueAfter: r.refreshInterval()}
+}
+
+// refreshInterval returns the interval of the spec or the manager, it is never shorter than minRefreshInterval
+func (r *SecretsSyncReconciler) refreshInterval() time.Duration {
+	interval := defaultRefreshInterval
+	if r.secretsSync.Spec.RefreshInterval != nil && r.secretsSync.Spec.RefreshInterval.Duration > 0 {
+		interval = r.secretsSync.Spec.RefreshInterval.Duration
+	} else if r.RefreshInterval > 0 {
+		interval = r.RefreshInterval
+	}
+
+	if interval < minRefreshInterval {
+		return minRefreshInterval
+	}
+
+	return interval
+}
+
+func (r *SecretsSyncReconciler) maxBackoff() time.Duration {
+	if r.MaxBackoff > 0 {
+		return r.MaxBackoff
+	}
+
+	return defaultMaxBackoff
+}
+
+func (r *SecretsSyncReconciler) reportMissingSource(err error, val internalv1alpha1.SrcSecret, srcSecretName string) {
+	srcKind := "secret"
+	if val.Kind == kindConfigMap {
+		srcKind = "config map"
+	}
+
+	message := fmt.Sprintf("Source namespace %s for %s %s not exist", val.SrcNamespace, srcKind, srcSecretName)
+
+	// The dry-run plan owns the phase, the missing sources are only logged
+	if r.dryRun() {
+		r.reqLogger.Info(message)
+		return
+	}
+
+	if r.secretsSync.Status.Phase != "PartiallySynced" && r.secretsSync.Status.Count > 0 {
+		r.reqLogger.Error(err, message)
+		r.updateStatusCRD("PartiallySynced", message, r.secretsSync.Status.Count)
+	}
+
+	if r.secretsSync.Status.Phase != "NotSynced" && r.secretsSync.Status.Count == 0 {
+		r.reqLogger.Error(err, message)
+		r.updateStatusCRD("NotSynced", message, 0)
+	}
 }
 
 func (r *SecretsSyncReconciler) updateStatusCRD(phase, errorCRD string, count int) {
@@ -184,128 +528,163 @@ func (r *SecretsSyncReconciler) updateStatusCRD(phase, errorCRD string, count in
 	}
 }
 
-func (r *SecretsSyncReconciler) GenerateSecrets(dstSecrets []internalv1alpha1.DstSecret, srcSecret *v1.Secret) []*v1.Secret {
-	var (
-		newSecrets   []*v1.Secret
-		secretLabels = map[string]string{
-			ownerKind: "SecretsSync",
-			ownerName: r.req.Name,
+// getSource reads the source Secret or ConfigMap, a ConfigMap is returned as an Opaque secret
+func (r *SecretsSyncReconciler) getSource(name string, val internalv1alpha1.SrcSecret) (*v1.Secret, error) {
+	key := types.NamespacedName{Name: name, Namespace: val.SrcNamespace}
+
+	if val.Kind == kindConfigMap {
+		srcConfigMap := &v1.ConfigMap{}
+		if err := r.Client.Get(r.ctx, key, srcConfigMap); err != nil {
+			return nil, err
 		}
-		secretName string
-	)
 
-	if len(dstSecrets) > 0 {
-		for _, dstSecret := range dstSecrets {
-			if len(dstSecret.Name) > 0 {
-				secretName = dstSecret.Name
-			} else {
-				secretName = srcSecret.Name
-			}
+		return configMapToSecret(srcConfigMap), nil
+	}
 
-			newSecret := &v1.Secret{
-				TypeMeta: secretMeta,
-				ObjectMeta: metav1.ObjectMeta{
-					Labels:    secretLabels,
-					Name:      secretName,
-					Namespace: r.req.Namespace,
-				},
-				Type: srcSecret.Type,
-			}
+	srcSecret := &v1.Secret{}
+	if err := r.Client.Get(r.ctx, key, srcSecret); err != nil {
+		return nil, err
+	}
 
-			data := make(map[string][]byte)
-			for key, val := range srcSecret.Data {
-				if keyName, ok := dstSecret.Keys[key]; ok {
-					data[keyName] = val
-				} else {
-					data[key] = val
-				}
-			}
+	return srcSecret, nil
+}
 
-			stringData := make(map[string]string)
-			for key, val := range srcSecret.StringData {
-				if keyName, ok := dstSecret.Keys[key]; ok {
-					stringData[keyName] = val
-				} else {
-					stringData[key] = val
-				}
-			}
+func (r *SecretsSyncReconciler) GenerateSecrets(val internalv1alpha1.SrcSecret, srcSecret *v1.Secret) ([]client.Object, error) {
+	objects, err := generateObjects(r.owner(), val, srcSecret)
 
-			newSecret.Data = data
-			newSecret.StringData = stringData
-			newSecrets = append(newSecrets, newSecret)
-		}
+	// The destinations exceeding the limits are never written, the previous ones are kept
+	objects, limitErrs := checkDestinationLimits(r.limits(), objects)
+	if len(limitErrs) == 0 {
+		return objects, err
+	}
 
-		return newSecrets
-	} else {
-		return append(newSecrets, &v1.Secret{
-			TypeMeta: secretMeta,
-			ObjectMeta: metav1.ObjectMeta{
-				Labels:    secretLabels,
-				Name:      srcSecret.Name,
-				Namespace: r.req.Namespace,
-			},
-			Data:       srcSecret.Data,
-			StringData: srcSecret.StringData,
-			Type:       srcSecret.Type,
-		})
+	if err != nil {
+		limitErrs = append([]error{err}, limitErrs...)
 	}
+
+	return objects, utilerrors.NewAggregate(limitErrs)
 }
 
-func (r *SecretsSyncReconciler) CreateSecret(secret *v1.Secret) error {
-	// Used to ensure that the secret will be deleted when the custom resource object is removed
-	if err := ctrl.SetControllerReference(r.secretsSync, secret, r.Scheme); err != nil {
-		return err
+func (r *SecretsSyncReconciler) owner() owner {
+	return owner{
+		kind:      "SecretsSync",
+		name:      r.req.Name,
+		namespace: r.req.Namespace,
 	}
+}
 
-	if err := r.Client.Create(r.ctx, secret); err != nil {
-		r.updateStatusCRD("Failed", err.Error(), 0)
+func (r *SecretsSyncReconciler) garbageCollector(objects ...client.Object) error {
+	orphans, err := orphanObjects(r.ctx, r.Client, r.owner(), objects...)
+	if err != nil {
 		return err
 	}
 
+	for _, item := range orphans {
+		if err := removeImagePullSecret(r.ctx, r.Client, item); err != nil {
+			return err
+		}
+
+		if err := removeObject(r.ctx, r.Client, item); err != nil {
+			return err
+		}
+
+		r.reqLogger.Info(fmt.Sprintf("%s removed %s/%s", objectKind(item), item.GetNamespace(), item.GetName()))
+		r.updateStatusCRD("Synced", "", len(objects))
+	}
+
 	return nil
 }
 
-func (r *SecretsSyncReconciler) garbageCollector(secrets ...*v1.Secret) error {
-	var (
-		secretLabels = map[string]string{
-			ownerKind: "SecretsSync",
-			ownerName: r.req.Name,
-		}
-	)
+// sourceKey identifies a source in the sourceIndex
+func sourceKey(kind, namespace, name string) string {
+	return kind + "/" + namespace + "/" + name
+}
+
+// indexSources returns the index values of the source namespaces and objects referenced by the SecretsSync
+func indexSources(obj client.Object, namespaces bool) []string {
+	var values []string
 
-	listSecrets := &v1.SecretList{}
-	listOps := &client.ListOptions{
-		LabelSelector: labels.SelectorFromSet(secretLabels),
-		Namespace:     r.req.Namespace,
+	secretsSync, ok := obj.(*internalv1alpha1.SecretsSync)
+	if !ok {
+		return nil
 	}
 
-	if err := r.Client.List(r.ctx, listSecrets, listOps); err != nil {
-		return err
+	for srcSecretName, val := range secretsSync.Spec.Secrets {
+		if namespaces {
+			values = append(values, val.SrcNamespace)
+			continue
+		}
+
+		values = append(values, sourceKey(srcKind(val), val.SrcNamespace, srcSecretName))
 	}
 
-	deleteListSecret := make(map[string]*v1.Secret, len(listSecrets.Items))
-	for _, secret := range secrets {
-		deleteListSecret[secret.Name] = secret
+	return values
+}
+
+// createdSourceKey returns the index and the value identifying the created namespace, Secret or ConfigMap
+func createdSourceKey(obj client.Object) (string, string) {
+	switch obj.(type) {
+	case *v1.Namespace:
+		return sourceNamespaceIndex, obj.GetName()
+	case *v1.ConfigMap:
+		return sourceIndex, sourceKey(kindConfigMap, obj.GetNamespace(), obj.GetName())
+	default:
+		return sourceIndex, sourceKey(kindSecret, obj.GetNamespace(), obj.GetName())
 	}
+}
 
-	for _, item := range listSecrets.Items {
-		if _, ok := deleteListSecret[item.Name]; !ok {
-			if err := r.Client.Delete(r.ctx, &item); err != nil {
-				return err
-			}
+// waitingSecretsSyncs enqueues the SecretsSync objects referencing the created namespace or source
+func (r *SecretsSyncReconciler) waitingSecretsSyncs(obj client.Object) []reconcile.Request {
+	var requests []reconcile.Request
 
-			r.reqLogger.Info(fmt.Sprintf("Secret removed %s", item.Name))
-			r.updateStatusCRD("Synced", "", len(secrets))
-		}
+	index, key := createdSourceKey(obj)
+
+	listSecretsSyncs := &internalv1alpha1.SecretsSyncList{}
+	if err := r.Client.List(context.Background(), listSecretsSyncs, client.MatchingFields{index: key}); err != nil {
+		log.Log.Error(err, fmt.Sprintf("Unable to list SecretsSync objects waiting for %s", key))
+		return nil
 	}
 
-	return nil
+	for _, item := range listSecretsSyncs.Items {
+		requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&item)})
+	}
+
+	return requests
 }
 
 // SetupWithManager sets up the controller with the Manager.
 func (r *SecretsSyncReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	baseDelay := r.RefreshInterval
+	if baseDelay <= 0 {
+		baseDelay = defaultRefreshInterval
+	}
+
+	if err := mgr.GetFieldIndexer().IndexField(context.Background(), &internalv1alpha1.SecretsSync{}, sourceNamespaceIndex,
+		func(obj client.Object) []string { return indexSources(obj, true) }); err != nil {
+		return err
+	}
+
+	if err := mgr.GetFieldIndexer().IndexField(context.Background(), &internalv1alpha1.SecretsSync{}, sourceIndex,
+		func(obj client.Object) []string { return indexSources(obj, false) }); err != nil {
+		return err
+	}
+
+	// Only creations are watched, so that the SecretsSync objects applied before their sources sync immediately
+	onlyCreate := builder.WithPredicates(predicate.Funcs{
+		UpdateFunc:  func(event.UpdateEvent) bool { return false },
+		DeleteFunc:  func(event.DeleteEvent) bool { return false },
+		GenericFunc: func(event.GenericEvent) bool { return false },
+	})
+
 	return ctrl.NewControllerManagedBy(mgr).
 		For(&internalv1alpha1.SecretsSync{}).
+		Watches(&source.Kind{Type: &v1.Namespace{}}, handler.EnqueueRequestsFromMapFunc(r.waitingSecretsSyncs), onlyCreate).
+		Watches(&source.Kind{Type: &v1.Secret{}}, handler.EnqueueRequestsFromMapFunc(r.waitingSecretsSyncs), onlyCreate).
+		Watches(&source.Kind{Type: &v1.ConfigMap{}}, handler.EnqueueRequestsFromMapFunc(r.waitingSecretsSyncs), onlyCreate).
 		WithEventFilter(predicate.GenerationChangedPredicate{}).
+		WithOptions(controller.Options{
+			// API errors are retried with an exponential delay instead of the fixed refresh interval
+			RateLimiter: jitterRateLimiter{workqueue.NewItemExponentialFailureRateLimiter(baseDelay, r.maxBackoff())},
+		}).
 		Complete(r)
 }
@@ -0,0 +1,415 @@
+/*
+Copyright 2025 Edenlab
+*/
+
+package controller
+
+import (
+	"context"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	internalv1alpha1 "secrets-sync.operators.infra/api/v1alpha1"
+)
+
+func newTestScheme(t *testing.T) *runtime.Scheme {
+	t.Helper()
+
+	scheme := runtime.NewScheme()
+	if err := clientgoscheme.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := internalv1alpha1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+
+	return scheme
+}
+
+func TestReconcileRejectsInvalidSpec(t *testing.T) {
+	tests := []struct {
+		name      string
+		dstSecret internalv1alpha1.DstSecret
+		wantField string
+	}{
+		{
+			name:      "reserved annotation",
+			dstSecret: internalv1alpha1.DstSecret{Annotations: map[string]string{syncModeAnnotation: modeMerge}},
+			wantField: "spec.secrets[app].dstSecrets[0].annotations",
+		},
+		{
+			// The expressions are type-checked without the admission webhook as well
+			name:      "invalid expression",
+			dstSecret: internalv1alpha1.DstSecret{When: "labels.env == 1 +"},
+			wantField: "spec.secrets[app].dstSecrets[0].when",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dstSecret := tt.dstSecret
+			dstSecret.Name = "app-copy"
+
+			secretsSync := &internalv1alpha1.SecretsSync{
+				ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "platform"},
+				Spec: internalv1alpha1.SecretsSyncSpec{Secrets: map[string]internalv1alpha1.SrcSecret{
+					"app": {SrcNamespace: "platform", DstSecrets: []internalv1alpha1.DstSecret{dstSecret}},
+				}},
+			}
+
+			r := newTestReconciler(t,
+				&v1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "platform"}},
+				&v1.Secret{
+					ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "platform"},
+					Data:       map[string][]byte{"password": []byte("secret")},
+					Type:       v1.SecretTypeOpaque,
+				},
+				secretsSync,
+			)
+
+			_, got := reconcileTestSync(t, r, secretsSync)
+			if got.Status.Phase != "Failed" || !strings.Contains(got.Status.Error, tt.wantField) {
+				t.Errorf("status = %s: %s, want Failed with %s", got.Status.Phase, got.Status.Error, tt.wantField)
+			}
+
+			err := r.Client.Get(context.Background(), client.ObjectKey{Name: "app-copy", Namespace: "platform"}, &v1.Secret{})
+			if err == nil {
+				t.Error("the destination of an invalid spec is synced")
+			}
+		})
+	}
+}
+
+// newTestReconciler returns a reconciler of the SecretsSync with a fake client holding the objects
+func newTestReconciler(t *testing.T, objects ...client.Object) *SecretsSyncReconciler {
+	t.Helper()
+
+	scheme := newTestScheme(t)
+
+	return &SecretsSyncReconciler{
+		SystemInfo: &SystemInfo{},
+		Scheme:     scheme,
+		Client:     fake.NewClientBuilder().WithScheme(scheme).WithObjects(objects...).Build(),
+		Recorder:   record.NewFakeRecorder(10),
+	}
+}
+
+// reconcileTestSync runs a sync of the SecretsSync and returns it with the updated status
+func reconcileTestSync(t *testing.T, r *SecretsSyncReconciler, secretsSync *internalv1alpha1.SecretsSync) (
+	ctrl.Result, *internalv1alpha1.SecretsSync) {
+	t.Helper()
+
+	key := client.ObjectKeyFromObject(secretsSync)
+	result, err := r.Reconcile(context.Background(), ctrl.Request{NamespacedName: key})
+	if err != nil {
+		t.Fatalf("Reconcile() error = %v", err)
+	}
+
+	got := &internalv1alpha1.SecretsSync{}
+	if err := r.Client.Get(context.Background(), key, got); err != nil {
+		t.Fatal(err)
+	}
+
+	return result, got
+}
+
+func TestReconcileOptionalSources(t *testing.T) {
+	secretsSync := &internalv1alpha1.SecretsSync{
+		ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "default"},
+		Spec: internalv1alpha1.SecretsSyncSpec{Secrets: map[string]internalv1alpha1.SrcSecret{
+			"app":       {SrcNamespace: "platform"},
+			"app-cache": {SrcNamespace: "platform", Optional: true},
+			"app-queue": {SrcNamespace: "queue", Optional: true},
+		}},
+	}
+
+	// The destination synced before the optional source was deleted
+	previous := newTestSecret(func(secret *v1.Secret) { secret.Name = "app-cache" })
+
+	r := newTestReconciler(t,
+		&v1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "platform"}},
+		&v1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "platform"},
+			Data:       map[string][]byte{"password": []byte("secret")},
+			Type:       v1.SecretTypeOpaque,
+		},
+		previous,
+		secretsSync,
+	)
+
+	result, got := reconcileTestSync(t, r, secretsSync)
+	if got.Status.Phase != "Synced" || len(got.Status.Error) > 0 {
+		t.Errorf("status = %s: %s, want Synced", got.Status.Phase, got.Status.Error)
+	}
+
+	// The missing optional sources do not delay the next sync
+	if result.RequeueAfter != defaultRefreshInterval || r.backoff.failures[client.ObjectKeyFromObject(secretsSync)] > 0 {
+		t.Errorf("next sync in %s, want the refresh interval %s", result.RequeueAfter, defaultRefreshInterval)
+	}
+
+	if err := r.Client.Get(context.Background(), client.ObjectKey{Namespace: "default", Name: "app"}, &v1.Secret{}); err != nil {
+		t.Errorf("destination of the present source: %v", err)
+	}
+
+	err := r.Client.Get(context.Background(), client.ObjectKeyFromObject(previous), &v1.Secret{})
+	if !errors.IsNotFound(err) {
+		t.Errorf("destination of the missing optional source error = %v, want it removed", err)
+	}
+}
+
+func TestReconcileKeepsDestinationWithoutRequiredKeys(t *testing.T) {
+	secretsSync := &internalv1alpha1.SecretsSync{
+		ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "default"},
+		Spec: internalv1alpha1.SecretsSyncSpec{Secrets: map[string]internalv1alpha1.SrcSecret{
+			"app": {SrcNamespace: "platform", DstSecrets: []internalv1alpha1.DstSecret{
+				{RequiredKeys: []string{"username", "password"}},
+			}},
+		}},
+	}
+
+	// The previous destination of the source and a destination removed from the spec since then
+	previous, removed := newTestSecret(nil), newTestSecret(func(secret *v1.Secret) { secret.Name = "app-old" })
+
+	r := newTestReconciler(t,
+		&v1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "platform"}},
+		&v1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "platform"},
+			Data:       map[string][]byte{"password": []byte("rotated")},
+			Type:       v1.SecretTypeOpaque,
+		},
+		previous,
+		removed,
+		secretsSync,
+	)
+
+	_, got := reconcileTestSync(t, r, secretsSync)
+
+	condition := meta.FindStatusCondition(got.Status.Conditions, conditionRequiredKeysMissing)
+	if condition == nil || condition.Status != metav1.ConditionTrue || !strings.Contains(condition.Message, "username") {
+		t.Errorf("RequiredKeysMissing condition = %v, want True with username", condition)
+	}
+
+	if got.Status.Phase != "Failed" || !strings.Contains(got.Status.Error, "missing required keys username") {
+		t.Errorf("status = %s: %s, want Failed with the missing keys", got.Status.Phase, got.Status.Error)
+	}
+
+	current := &v1.Secret{}
+	if err := r.Client.Get(context.Background(), client.ObjectKeyFromObject(previous), current); err != nil {
+		t.Fatalf("previous destination: %v", err)
+	}
+
+	if !reflect.DeepEqual(current.Data, previous.Data) {
+		t.Errorf("previous destination data = %v, want it kept", keysOf(current.Data))
+	}
+
+	// The garbage collection is skipped until the sources generate successfully
+	if err := r.Client.Get(context.Background(), client.ObjectKeyFromObject(removed), &v1.Secret{}); err != nil {
+		t.Errorf("destination removed from the spec: %v, want it kept", err)
+	}
+}
+
+func newTestIndexedSyncs() []client.Object {
+	return []client.Object{
+		&internalv1alpha1.SecretsSync{
+			ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "platform"},
+			Spec: internalv1alpha1.SecretsSyncSpec{Secrets: map[string]internalv1alpha1.SrcSecret{
+				"db":     {SrcNamespace: "databases"},
+				"config": {SrcNamespace: "platform", Kind: kindConfigMap},
+			}},
+		},
+		&internalv1alpha1.SecretsSync{
+			ObjectMeta: metav1.ObjectMeta{Name: "worker", Namespace: "team-a"},
+			Spec: internalv1alpha1.SecretsSyncSpec{Secrets: map[string]internalv1alpha1.SrcSecret{
+				"db": {SrcNamespace: "databases", Kind: kindSecret},
+			}},
+		},
+	}
+}
+
+func TestIndexSources(t *testing.T) {
+	secretsSync := newTestIndexedSyncs()[0]
+
+	tests := []struct {
+		name       string
+		obj        client.Object
+		namespaces bool
+		want       []string
+	}{
+		{name: "namespaces", obj: secretsSync, namespaces: true, want: []string{"databases", "platform"}},
+		{
+			name: "sources",
+			obj:  secretsSync,
+			want: []string{"ConfigMap/platform/config", "Secret/databases/db"},
+		},
+		{name: "other object", obj: &v1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "db", Namespace: "databases"}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := indexSources(tt.obj, tt.namespaces)
+			sort.Strings(got)
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("indexSources() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWaitingSecretsSyncs(t *testing.T) {
+	scheme := newTestScheme(t)
+	r := &SecretsSyncReconciler{
+		SystemInfo: &SystemInfo{},
+		Scheme:     scheme,
+		Client: fake.NewClientBuilder().WithScheme(scheme).WithObjects(newTestIndexedSyncs()...).
+			WithIndex(&internalv1alpha1.SecretsSync{}, sourceNamespaceIndex,
+				func(obj client.Object) []string { return indexSources(obj, true) }).
+			WithIndex(&internalv1alpha1.SecretsSync{}, sourceIndex,
+				func(obj client.Object) []string { return indexSources(obj, false) }).
+			Build(),
+	}
+
+	tests := []struct {
+		name string
+		obj  client.Object
+		want []string
+	}{
+		{
+			name: "source namespace",
+			obj:  &v1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "databases"}},
+			want: []string{"platform/app", "team-a/worker"},
+		},
+		{name: "other namespace", obj: &v1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "team-b"}}},
+		{
+			name: "source secret",
+			obj:  &v1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "db", Namespace: "databases"}},
+			want: []string{"platform/app", "team-a/worker"},
+		},
+		{
+			name: "source config map",
+			obj:  &v1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "config", Namespace: "platform"}},
+			want: []string{"platform/app"},
+		},
+		{
+			// The kind of the source is a part of the key
+			name: "config map named as a source secret",
+			obj:  &v1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "db", Namespace: "databases"}},
+		},
+		{
+			name: "secret named as a source config map",
+			obj:  &v1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "config", Namespace: "platform"}},
+		},
+		{
+			name: "source name in other namespace",
+			obj:  &v1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "db", Namespace: "team-a"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, req := range r.waitingSecretsSyncs(tt.obj) {
+				got = append(got, req.String())
+			}
+
+			sort.Strings(got)
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("waitingSecretsSyncs() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReconcileConfigMapDestinations(t *testing.T) {
+	ctx := context.Background()
+	secretsSync := &internalv1alpha1.SecretsSync{
+		ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "default"},
+		Spec: internalv1alpha1.SecretsSyncSpec{Secrets: map[string]internalv1alpha1.SrcSecret{
+			"config": {SrcNamespace: "platform", Kind: kindConfigMap, DstSecrets: []internalv1alpha1.DstSecret{
+				{Name: "config"},
+				{Name: "config-copy"},
+			}},
+		}},
+	}
+
+	r := newTestReconciler(t,
+		&v1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "platform"}},
+		&v1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "default"}},
+		&v1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: "config", Namespace: "platform"},
+			Data:       map[string]string{"host": "db"},
+			BinaryData: map[string][]byte{"cert.der": {0xff, 0xfe}},
+		},
+		secretsSync,
+	)
+
+	wantData, wantBinaryData := map[string]string{"host": "db"}, map[string][]byte{"cert.der": {0xff, 0xfe}}
+	checkDestination := func(name string) {
+		t.Helper()
+
+		got := &v1.ConfigMap{}
+		if err := r.Client.Get(ctx, client.ObjectKey{Name: name, Namespace: "default"}, got); err != nil {
+			t.Fatalf("destination %s: %v", name, err)
+		}
+
+		if !reflect.DeepEqual(got.Data, wantData) || !reflect.DeepEqual(got.BinaryData, wantBinaryData) {
+			t.Errorf("destination %s = %v %v, want %v %v", name, got.Data, got.BinaryData, wantData, wantBinaryData)
+		}
+	}
+
+	if _, got := reconcileTestSync(t, r, secretsSync); got.Status.Phase != "Synced" {
+		t.Fatalf("status = %s: %s, want Synced", got.Status.Phase, got.Status.Error)
+	}
+
+	checkDestination("config")
+	checkDestination("config-copy")
+
+	// The changed, added and removed keys of a destination are drift
+	drifted := &v1.ConfigMap{}
+	if err := r.Client.Get(ctx, client.ObjectKey{Name: "config", Namespace: "default"}, drifted); err != nil {
+		t.Fatal(err)
+	}
+
+	drifted.Data = map[string]string{"host": "replica", "port": "5432"}
+	drifted.BinaryData = nil
+	if err := r.Client.Update(ctx, drifted); err != nil {
+		t.Fatal(err)
+	}
+
+	current := &internalv1alpha1.SecretsSync{}
+	if err := r.Client.Get(ctx, client.ObjectKeyFromObject(secretsSync), current); err != nil {
+		t.Fatal(err)
+	}
+
+	src := current.Spec.Secrets["config"]
+	src.DstSecrets = src.DstSecrets[:1]
+	current.Spec.Secrets["config"] = src
+	if err := r.Client.Update(ctx, current); err != nil {
+		t.Fatal(err)
+	}
+
+	reconcileTestSync(t, r, secretsSync)
+
+	checkDestination("config")
+
+	// The destination removed from the spec is collected
+	err := r.Client.Get(ctx, client.ObjectKey{Name: "config-copy", Namespace: "default"}, &v1.ConfigMap{})
+	if !errors.IsNotFound(err) {
+		t.Errorf("removed destination: %v, want it collected", err)
+	}
+}
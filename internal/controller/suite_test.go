@@ -5,6 +5,7 @@ Copyright 2025 Edenlab
 package controller
 
 import (
+	"os"
 	"path/filepath"
 	"testing"
 
@@ -38,6 +39,11 @@ func TestAPIs(t *testing.T) {
 var _ = BeforeSuite(func() {
 	logf.SetLogger(zap.New(zap.WriteTo(GinkgoWriter), zap.UseDevMode(true)))
 
+	// The control plane binaries are installed by make test, plain go test runs only the unit tests
+	if len(os.Getenv("KUBEBUILDER_ASSETS")) == 0 {
+		Skip("KUBEBUILDER_ASSETS is not set, the envtest specs are run by make test")
+	}
+
 	By("bootstrapping test environment")
 	testEnv = &envtest.Environment{
 		CRDDirectoryPaths:     []string{filepath.Join("..", "..", "config", "crd", "bases")},
@@ -62,6 +68,10 @@ var _ = BeforeSuite(func() {
 })
 
 var _ = AfterSuite(func() {
+	if testEnv == nil || cfg == nil {
+		return
+	}
+
 	By("tearing down the test environment")
 	err := testEnv.Stop()
 	Expect(err).NotTo(HaveOccurred())
@@ -0,0 +1,427 @@
+/*
+Copyright 2025 Edenlab
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	internalv1alpha1 "secrets-sync.operators.infra/api/v1alpha1"
+	"secrets-sync.operators.infra/internal/expression"
+	"secrets-sync.operators.infra/internal/naming"
+)
+
+var errNotOwned = fmt.Errorf("object already exists and is not managed by the operator")
+
+// owner identifies the object the generated secrets and config maps belong to,
+// it is either a SecretsSync or an annotated source Secret
+type owner struct {
+	kind      string
+	name      string
+	namespace string
+}
+
+func (o owner) labels() map[string]string {
+	return map[string]string{
+		ownerKind:      o.kind,
+		ownerName:      o.name,
+		ownerNamespace: o.namespace,
+	}
+}
+
+// owns reports whether the labeled object belongs to the owner and not to
+// an owner with the same name in another namespace
+func (o owner) owns(obj client.Object) bool {
+	objLabels := obj.GetLabels()
+	if objLabels[ownerKind] != o.kind || objLabels[ownerName] != o.name {
+		return false
+	}
+
+	namespace, ok := objLabels[ownerNamespace]
+	if !ok {
+		// Objects created before the owner namespace label was introduced
+		return obj.GetNamespace() == o.namespace
+	}
+
+	return namespace == o.namespace
+}
+
+// canWrite reports whether the existing object may be written by the owner, the unowned objects are only
+// adopted with adopt set and the objects of other SecretsSyncs or replicas are never merged into
+func (o owner) canWrite(current, desired client.Object, adopt bool) bool {
+	if o.owns(current) {
+		return true
+	}
+
+	if isMerged(desired) {
+		_, ok := current.GetLabels()[ownerKind]
+		return adopt && !ok
+	}
+
+	return adopt
+}
+
+// generateObjects builds the destination secrets and config maps of a single source,
+// destinations without namespaces are created in the namespace of the owner
+func generateObjects(o owner, val internalv1alpha1.SrcSecret, srcSecret *v1.Secret) ([]client.Object, error) {
+	var (
+		newObjects []client.Object
+		secretName string
+		dstSecrets = val.DstSecrets
+		errs       []error
+	)
+
+	// Without destinations the source is copied as is
+	if len(dstSecrets) == 0 {
+		dstSecrets = []internalv1alpha1.DstSecret{{}}
+	}
+
+	for _, dstSecret := range dstSecrets {
+		if len(dstSecret.Name) > 0 {
+			secretName = dstSecret.Name
+		} else {
+			secretName = srcSecret.Name
+		}
+
+		// The merged objects are shared with other tools, an immutable one could never be released
+		if dstSecret.Immutable && dstSecret.Mode == modeMerge {
+			errs = append(errs, fmt.Errorf("destination %s: immutable is not supported with the Merge mode", secretName))
+			continue
+		}
+
+		// The config maps have no type, it would be silently dropped
+		if len(dstSecret.Type) > 0 && dstKind(val, dstSecret) == kindConfigMap {
+			errs = append(errs, fmt.Errorf("destination %s: type is not supported for a ConfigMap destination", secretName))
+			continue
+		}
+
+		if len(dstSecret.When) > 0 {
+			produce, err := expression.EvalPredicate(dstSecret.When, expressionSource(srcSecret))
+			if err != nil {
+				errs = append(errs, fmt.Errorf("destination %s: when: %w", secretName, err))
+				continue
+			}
+
+			// A skipped destination is garbage-collected like a removed one
+			if !produce {
+				continue
+			}
+		}
+
+		data, stringData, err := mapKeys(dstSecret, srcSecret)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("destination %s: %w", secretName, err))
+			continue
+		}
+
+		if err := computeKeys(dstSecret, srcSecret, data); err != nil {
+			errs = append(errs, fmt.Errorf("destination %s: %w", secretName, err))
+			continue
+		}
+
+		if err := checkRequiredKeys(dstSecret, mergeStringData(data, stringData)); err != nil {
+			errs = append(errs, fmt.Errorf("destination %s: %w", secretName, err))
+			continue
+		}
+
+		if len(dstSecret.Transforms) > 0 || dstSecret.Bundle != nil || len(dstSecret.Steps) > 0 {
+			transformed, err := transformData(dstSecret, mergeStringData(data, stringData))
+			if err != nil {
+				errs = append(errs, fmt.Errorf("destination %s: %w", secretName, err))
+				continue
+			}
+
+			data, stringData = transformed, nil
+		}
+
+		secretType := srcSecret.Type
+		if len(dstSecret.Format) > 0 {
+			formatted, formatType, err := formatData(dstSecret, mergeStringData(data, stringData))
+			if err != nil {
+				errs = append(errs, fmt.Errorf("destination %s: %w", secretName, err))
+				continue
+			}
+
+			data, stringData, secretType = formatted, nil, formatType
+		}
+
+		if dstSecret.Keystore != nil {
+			withKeystores, err := addKeystores(dstSecret.Keystore, mergeStringData(data, stringData))
+			if err != nil {
+				errs = append(errs, fmt.Errorf("destination %s: %w", secretName, err))
+				continue
+			}
+
+			data, stringData = withKeystores, nil
+		}
+
+		if len(dstSecret.Type) > 0 {
+			if len(dstSecret.Format) > 0 && dstSecret.Type != secretType {
+				errs = append(errs, fmt.Errorf("destination %s: type %s conflicts with format %s",
+					secretName, dstSecret.Type, dstSecret.Format))
+				continue
+			}
+
+			secretType = dstSecret.Type
+		}
+
+		// The required keys are checked before the API server rejects the secret
+		if dstKind(val, dstSecret) == kindSecret {
+			if err := validateSecretType(secretType, mergeStringData(data, stringData)); err != nil {
+				errs = append(errs, fmt.Errorf("destination %s: %w", secretName, err))
+				continue
+			}
+		}
+
+		// The merged secrets keep the type of the existing object unless it is set explicitly
+		if dstSecret.Mode == modeMerge {
+			data, stringData = mergeStringData(data, stringData), nil
+			if len(dstSecret.Type) == 0 && len(dstSecret.Format) == 0 {
+				secretType = ""
+			}
+		}
+
+		dstLabels, dstAnnotations := destinationMetadata(dstSecret, srcSecret)
+
+		namespaces := dstSecret.Namespaces
+		if len(namespaces) == 0 {
+			namespaces = []string{o.namespace}
+		}
+
+		for _, namespace := range namespaces {
+			name, err := naming.Render(secretName, naming.Vars{
+				SourceName:      srcSecret.Name,
+				SourceNamespace: val.SrcNamespace,
+				SourceKind:      srcKind(val),
+				Namespace:       namespace,
+			})
+			if err != nil {
+				errs = append(errs, fmt.Errorf("destination %s: %w", secretName, err))
+				continue
+			}
+
+			// The ownership labels always win over the propagated and extra ones
+			meta := metav1.ObjectMeta{
+				Labels:    mergeMetadata(mergeMetadata(map[string]string{}, dstLabels), o.labels()),
+				Name:      name,
+				Namespace: namespace,
+			}
+
+			if len(dstAnnotations) > 0 {
+				meta.Annotations = mergeMetadata(map[string]string{}, dstAnnotations)
+			}
+
+			if dstKind(val, dstSecret) == kindConfigMap {
+				configMap := newConfigMap(meta, data)
+				markImmutable(configMap, dstSecret)
+				markKeystores(configMap, dstSecret)
+				if dstSecret.Mode == modeMerge {
+					markMerged(configMap)
+				}
+
+				newObjects = append(newObjects, configMap)
+				continue
+			}
+
+			if len(dstSecret.ImagePullServiceAccounts) > 0 {
+				if meta.Annotations == nil {
+					meta.Annotations = make(map[string]string)
+				}
+
+				// The annotation is managed, so that its removal is detected as drift
+				meta.Annotations[imagePullServiceAccountsAnnotation] = strings.Join(dstSecret.ImagePullServiceAccounts, ",")
+				meta.Annotations[managedAnnotationsAnnotation] = addMetadataKey(
+					meta.Annotations[managedAnnotationsAnnotation], imagePullServiceAccountsAnnotation)
+			}
+
+			// The API server never returns the string data, it is stored in the data
+			secret := &v1.Secret{
+				TypeMeta:   secretMeta,
+				ObjectMeta: meta,
+				Data:       mergeStringData(data, stringData),
+				Type:       secretType,
+			}
+
+			markImmutable(secret, dstSecret)
+			markKeystores(secret, dstSecret)
+			if dstSecret.Mode == modeMerge {
+				markMerged(secret)
+			}
+
+			newObjects = append(newObjects, secret)
+		}
+	}
+
+	return newObjects, utilerrors.NewAggregate(errs)
+}
+
+// mergeStringData returns the data with the string data on top of it, as the API server stores them
+func mergeStringData(data map[string][]byte, stringData map[string]string) map[string][]byte {
+	merged := make(map[string][]byte, len(data)+len(stringData))
+	for key, val := range data {
+		merged[key] = val
+	}
+
+	for key, val := range stringData {
+		merged[key] = []byte(val)
+	}
+
+	return merged
+}
+
+// dropCollisions removes the objects generated more than once with the same kind, namespace and name,
+// none of them is synced, so that the sources do not overwrite each other on every sync
+func dropCollisions(objects []client.Object) ([]client.Object, error) {
+	var (
+		unique     []client.Object
+		collisions []string
+		counts     = make(map[string]int, len(objects))
+	)
+
+	for _, obj := range objects {
+		counts[objectID(obj)]++
+	}
+
+	for _, obj := range objects {
+		if counts[objectID(obj)] == 1 {
+			unique = append(unique, obj)
+		}
+	}
+
+	for id, count := range counts {
+		if count > 1 {
+			collisions = append(collisions, id)
+		}
+	}
+
+	if len(collisions) == 0 {
+		return objects, nil
+	}
+
+	sort.Strings(collisions)
+
+	return unique, fmt.Errorf("destinations generated more than once: %s", strings.Join(collisions, ", "))
+}
+
+func srcKind(val internalv1alpha1.SrcSecret) string {
+	if val.Kind == kindConfigMap {
+		return kindConfigMap
+	}
+
+	return kindSecret
+}
+
+// dstKind returns the kind of the destination object, by default it matches the kind of the source
+func dstKind(val internalv1alpha1.SrcSecret, dstSecret internalv1alpha1.DstSecret) string {
+	if len(dstSecret.Kind) > 0 {
+		return dstSecret.Kind
+	}
+
+	if val.Kind == kindConfigMap {
+		return kindConfigMap
+	}
+
+	return kindSecret
+}
+
+// syncObject creates the object, updates it in place or recreates it when the type or an immutable object differs,
+// the merged objects are applied instead, the returned action is empty when the object is already in sync.
+// Existing objects without the owner labels are only replaced when adopt is set.
+func syncObject(ctx context.Context, c client.Client, o owner, obj client.Object, adopt bool) (string, error) {
+	if isMerged(obj) {
+		return mergeObject(ctx, c, o, obj, adopt)
+	}
+
+	defObject := emptyObject(obj)
+	if err := c.Get(ctx, client.ObjectKeyFromObject(obj), defObject); err != nil {
+		if errors.IsNotFound(err) {
+			return planActionCreate, c.Create(ctx, obj)
+		}
+
+		return "", err
+	}
+
+	if !o.canWrite(defObject, obj, adopt) {
+		return "", errNotOwned
+	}
+
+	keepKeystores(defObject, obj)
+
+	action := objectChange(defObject, obj)
+	if err := checkImmutable(defObject, obj, action); err != nil {
+		return "", err
+	}
+
+	if len(action) == 0 {
+		return "", nil
+	}
+
+	// The update replaces the whole object, the metadata of other tools is kept
+	preserveMetadata(defObject, obj)
+
+	if action == planActionUpdate {
+		// The generated objects have no finalizers, the ones of other tools are kept as well
+		obj.SetFinalizers(defObject.GetFinalizers())
+		obj.SetResourceVersion(defObject.GetResourceVersion())
+		return planActionUpdate, c.Update(ctx, obj)
+	}
+
+	if err := c.Delete(ctx, obj); err != nil {
+		return "", err
+	}
+
+	return planActionRecreate, c.Create(ctx, obj)
+}
+
+// orphanObjects returns the secrets and config maps of the owner in all namespaces
+// which are not among the generated objects
+func orphanObjects(ctx context.Context, c client.Client, o owner, objects ...client.Object) ([]client.Object, error) {
+	var orphans []client.Object
+
+	listOps := &client.ListOptions{
+		LabelSelector: labels.SelectorFromSet(map[string]string{
+			ownerKind: o.kind,
+			ownerName: o.name,
+		}),
+	}
+
+	listSecrets := &v1.SecretList{}
+	if err := c.List(ctx, listSecrets, listOps); err != nil {
+		return nil, err
+	}
+
+	listConfigMaps := &v1.ConfigMapList{}
+	if err := c.List(ctx, listConfigMaps, listOps); err != nil {
+		return nil, err
+	}
+
+	deleteList := make(map[string]client.Object, len(objects))
+	for _, obj := range objects {
+		deleteList[objectID(obj)] = obj
+	}
+
+	for i := range listSecrets.Items {
+		if _, ok := deleteList[objectID(&listSecrets.Items[i])]; !ok && o.owns(&listSecrets.Items[i]) {
+			orphans = append(orphans, &listSecrets.Items[i])
+		}
+	}
+
+	for i := range listConfigMaps.Items {
+		if _, ok := deleteList[objectID(&listConfigMaps.Items[i])]; !ok && o.owns(&listConfigMaps.Items[i]) {
+			orphans = append(orphans, &listConfigMaps.Items[i])
+		}
+	}
+
+	return orphans, nil
+}
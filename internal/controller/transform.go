@@ -0,0 +1,45 @@
+/*
+Copyright 2025 Edenlab
+*/
+
+package controller
+
+import (
+	"strconv"
+	"strings"
+
+	internalv1alpha1 "secrets-sync.operators.infra/api/v1alpha1"
+	"secrets-sync.operators.infra/internal/transformer"
+)
+
+// transformData runs the transforms, the bundle and the steps of the destination through the transformer registry
+func transformData(dstSecret internalv1alpha1.DstSecret, data map[string][]byte) (map[string][]byte, error) {
+	var steps []transformer.Step
+
+	for _, transform := range dstSecret.Transforms {
+		for _, operation := range transform.Operations {
+			steps = append(steps, transformer.Step{
+				Name:   string(operation),
+				Params: map[string]string{transformer.ParamKeys: strings.Join(transform.Keys, ",")},
+			})
+		}
+	}
+
+	if dstSecret.Bundle != nil {
+		steps = append(steps, transformer.Step{
+			Name: transformer.Bundle,
+			Params: map[string]string{
+				transformer.ParamKey:      dstSecret.Bundle.Key,
+				transformer.ParamFormat:   dstSecret.Bundle.Format,
+				transformer.ParamKeys:     strings.Join(dstSecret.Bundle.Keys, ","),
+				transformer.ParamKeepKeys: strconv.FormatBool(dstSecret.Bundle.KeepKeys),
+			},
+		})
+	}
+
+	for _, step := range dstSecret.Steps {
+		steps = append(steps, transformer.Step{Name: step.Name, Params: step.Params})
+	}
+
+	return transformer.Apply(steps, data)
+}
@@ -0,0 +1,84 @@
+/*
+Copyright 2025 Edenlab
+*/
+
+package controller
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	internalv1alpha1 "secrets-sync.operators.infra/api/v1alpha1"
+)
+
+func TestTransformDataOrder(t *testing.T) {
+	dstSecret := internalv1alpha1.DstSecret{
+		Transforms: []internalv1alpha1.KeyTransform{{
+			Keys:       []string{"password"},
+			Operations: []internalv1alpha1.TransformOperation{"trimSpace"},
+		}},
+		Bundle: &internalv1alpha1.Bundle{Key: ".env", Format: "env"},
+		Steps:  []internalv1alpha1.TransformerStep{{Name: "base64Encode"}},
+	}
+
+	// The transforms run before the bundle and the steps after it
+	got, err := transformData(dstSecret, map[string][]byte{"password": []byte(" secret\n")})
+	if err != nil {
+		t.Fatalf("transformData() error = %v", err)
+	}
+
+	if want := "cGFzc3dvcmQ9c2VjcmV0Cg=="; len(got) != 1 || string(got[".env"]) != want {
+		t.Errorf("transformData() = %q, want .env %s", got, want)
+	}
+}
+
+func TestReconcileReportsStepError(t *testing.T) {
+	scheme := newTestScheme(t)
+
+	secretsSync := &internalv1alpha1.SecretsSync{
+		ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "platform"},
+		Spec: internalv1alpha1.SecretsSyncSpec{Secrets: map[string]internalv1alpha1.SrcSecret{
+			"app": {SrcNamespace: "platform", DstSecrets: []internalv1alpha1.DstSecret{{
+				Name: "app-decoded",
+				Steps: []internalv1alpha1.TransformerStep{
+					{Name: "trimSpace"},
+					{Name: "hexDecode", Params: map[string]string{"keys": "password"}},
+				},
+			}}},
+		}},
+	}
+
+	r := &SecretsSyncReconciler{
+		SystemInfo: &SystemInfo{},
+		Scheme:     scheme,
+		Client: fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+			&v1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "platform"}},
+			&v1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "platform"},
+				Data:       map[string][]byte{"password": []byte("not hex")},
+				Type:       v1.SecretTypeOpaque,
+			},
+			secretsSync,
+		).Build(),
+	}
+
+	if _, err := r.Reconcile(context.Background(), ctrl.Request{NamespacedName: client.ObjectKeyFromObject(secretsSync)}); err != nil {
+		t.Fatalf("Reconcile() error = %v", err)
+	}
+
+	got := &internalv1alpha1.SecretsSync{}
+	if err := r.Client.Get(context.Background(), client.ObjectKeyFromObject(secretsSync), got); err != nil {
+		t.Fatal(err)
+	}
+
+	if got.Status.Phase != "Failed" || !strings.Contains(got.Status.Error, "destination app-decoded: step 1 (hexDecode): key password") {
+		t.Errorf("status = %s: %s, want Failed with the failed step", got.Status.Phase, got.Status.Error)
+	}
+}
@@ -0,0 +1,234 @@
+/*
+Copyright 2025 Edenlab
+*/
+
+// Package expression compiles and evaluates the CEL expressions of the destinations.
+//
+// The expressions see the source object through the variables:
+//
+//	name, srcNamespace, secretType string
+//
+// The namespace of the source is named srcNamespace, namespace is a reserved word of CEL.
+//
+//	labels, annotations         map(string, string)
+//	data                        map(string, bytes)
+package expression
+
+import (
+	"container/list"
+	"fmt"
+	"sync"
+
+	"github.com/google/cel-go/cel"
+	"github.com/google/cel-go/ext"
+)
+
+const (
+	// CostLimit stops the evaluation of an expression exceeding the runtime cost
+	CostLimit = 1000000
+	// MaxLength is the maximum length of an expression
+	MaxLength = 4096
+	// maxPrograms is the number of the compiled programs kept for the evaluation
+	maxPrograms = 1024
+)
+
+var (
+	envOnce sync.Once
+	env     *cel.Env
+	envErr  error
+
+	programs = newProgramCache(maxPrograms)
+)
+
+// Source is the source object seen by the expressions
+type Source struct {
+	Name        string
+	Namespace   string
+	Type        string
+	Labels      map[string]string
+	Annotations map[string]string
+	Data        map[string][]byte
+}
+
+func newEnv() (*cel.Env, error) {
+	envOnce.Do(func() {
+		env, envErr = cel.NewEnv(
+			cel.Variable("name", cel.StringType),
+			cel.Variable("srcNamespace", cel.StringType),
+			cel.Variable("secretType", cel.StringType),
+			cel.Variable("labels", cel.MapType(cel.StringType, cel.StringType)),
+			cel.Variable("annotations", cel.MapType(cel.StringType, cel.StringType)),
+			cel.Variable("data", cel.MapType(cel.StringType, cel.BytesType)),
+			ext.Strings(),
+			ext.Encoders(),
+		)
+	})
+
+	return env, envErr
+}
+
+// CheckPredicate type-checks an expression returning a bool
+func CheckPredicate(expr string) error {
+	_, err := compile(expr, cel.BoolType)
+	return err
+}
+
+// CheckValue type-checks an expression returning a string or bytes
+func CheckValue(expr string) error {
+	_, err := compile(expr, cel.StringType, cel.BytesType)
+	return err
+}
+
+// EvalPredicate evaluates the bool expression against the source
+func EvalPredicate(expr string, src Source) (bool, error) {
+	val, err := eval(expr, src, cel.BoolType)
+	if err != nil {
+		return false, err
+	}
+
+	result, ok := val.(bool)
+	if !ok {
+		return false, fmt.Errorf("expression %q returned %T, expected bool", expr, val)
+	}
+
+	return result, nil
+}
+
+// EvalValue evaluates the string or bytes expression against the source
+func EvalValue(expr string, src Source) ([]byte, error) {
+	val, err := eval(expr, src, cel.StringType, cel.BytesType)
+	if err != nil {
+		return nil, err
+	}
+
+	switch result := val.(type) {
+	case string:
+		return []byte(result), nil
+	case []byte:
+		return result, nil
+	}
+
+	return nil, fmt.Errorf("expression %q returned %T, expected string or bytes", expr, val)
+}
+
+func eval(expr string, src Source, outputTypes ...*cel.Type) (interface{}, error) {
+	key := fmt.Sprint(outputTypes) + "/" + expr
+
+	program, ok := programs.get(key)
+	if !ok {
+		var err error
+		if program, err = compile(expr, outputTypes...); err != nil {
+			return nil, err
+		}
+
+		programs.add(key, program)
+	}
+
+	val, _, err := program.Eval(map[string]interface{}{
+		"name":         src.Name,
+		"srcNamespace": src.Namespace,
+		"secretType":   src.Type,
+		"labels":       nonNil(src.Labels),
+		"annotations":  nonNil(src.Annotations),
+		"data":         src.Data,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("expression %q: %w", expr, err)
+	}
+
+	return val.Value(), nil
+}
+
+// compile returns the program of the expression checked for one of the output types, it is not cached,
+// so that the expressions checked by the webhook, including the rejected ones, do not fill the cache
+func compile(expr string, outputTypes ...*cel.Type) (cel.Program, error) {
+	if len(expr) > MaxLength {
+		return nil, fmt.Errorf("expression is longer than %d characters", MaxLength)
+	}
+
+	celEnv, err := newEnv()
+	if err != nil {
+		return nil, err
+	}
+
+	ast, issues := celEnv.Compile(expr)
+	if issues != nil && issues.Err() != nil {
+		return nil, fmt.Errorf("expression %q: %w", expr, issues.Err())
+	}
+
+	matched := false
+	for _, outputType := range outputTypes {
+		if outputType.IsAssignableType(ast.OutputType()) {
+			matched = true
+		}
+	}
+
+	if !matched {
+		return nil, fmt.Errorf("expression %q returns %s, expected %v", expr, ast.OutputType(), outputTypes)
+	}
+
+	program, err := celEnv.Program(ast, cel.CostLimit(CostLimit), cel.InterruptCheckFrequency(100))
+	if err != nil {
+		return nil, err
+	}
+
+	return program, nil
+}
+
+// programCache keeps the most recently evaluated programs, the least recently used one is evicted
+// when the size is exceeded
+type programCache struct {
+	mu    sync.Mutex
+	size  int
+	order *list.List
+	items map[string]*list.Element
+}
+
+type cacheEntry struct {
+	key     string
+	program cel.Program
+}
+
+func newProgramCache(size int) *programCache {
+	return &programCache{size: size, order: list.New(), items: make(map[string]*list.Element)}
+}
+
+func (c *programCache) get(key string) (cel.Program, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	elem, ok := c.items[key]
+	if !ok {
+		return nil, false
+	}
+
+	c.order.MoveToFront(elem)
+
+	return elem.Value.(*cacheEntry).program, true
+}
+
+func (c *programCache) add(key string, program cel.Program) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if elem, ok := c.items[key]; ok {
+		c.order.MoveToFront(elem)
+		return
+	}
+
+	c.items[key] = c.order.PushFront(&cacheEntry{key: key, program: program})
+
+	if c.order.Len() > c.size {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.items, oldest.Value.(*cacheEntry).key)
+	}
+}
+
+func nonNil(m map[string]string) map[string]string {
+	if m == nil {
+		return map[string]string{}
+	}
+
+	return m
+}
@@ -0,0 +1,163 @@
+/*
+Copyright 2025 Edenlab
+*/
+
+package expression
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/google/cel-go/cel"
+)
+
+var testSource = Source{
+	Name:      "db",
+	Namespace: "prod",
+	Type:      "Opaque",
+	Labels:    map[string]string{"env": "prod"},
+	Data:      map[string][]byte{"username": []byte("admin"), "password": []byte("secret")},
+}
+
+func TestCheck(t *testing.T) {
+	tests := []struct {
+		name    string
+		check   func(string) error
+		expr    string
+		wantErr bool
+	}{
+		{name: "predicate", check: CheckPredicate, expr: "'password' in data && labels.env == 'prod'"},
+		{name: "predicate returning string", check: CheckPredicate, expr: "name", wantErr: true},
+		{name: "string value", check: CheckValue, expr: "name + '.' + srcNamespace"},
+		{name: "bytes value", check: CheckValue, expr: "data.password"},
+		{name: "string extension", check: CheckValue, expr: "name.upperAscii()"},
+		{name: "base64 extension", check: CheckValue, expr: "base64.encode(data.password)"},
+		{name: "value returning bool", check: CheckValue, expr: "true", wantErr: true},
+		{name: "syntax error", check: CheckPredicate, expr: "name ==", wantErr: true},
+		{name: "unknown variable", check: CheckPredicate, expr: "spec.enabled", wantErr: true},
+		{name: "reserved word", check: CheckValue, expr: "namespace", wantErr: true},
+		{name: "too long", check: CheckValue, expr: "'" + strings.Repeat("a", MaxLength) + "'", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.check(tt.expr); (err != nil) != tt.wantErr {
+				t.Errorf("check(%q) error = %v, wantErr %v", tt.expr, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestEvalPredicate(t *testing.T) {
+	tests := []struct {
+		expr    string
+		want    bool
+		wantErr bool
+	}{
+		{expr: "'password' in data && labels.env == 'prod'", want: true},
+		{expr: "'token' in data", want: false},
+		{expr: "annotations.size() == 0", want: true},
+		{expr: "secretType == 'kubernetes.io/tls'", want: false},
+		{expr: "labels.team == 'platform'", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			got, err := EvalPredicate(tt.expr, testSource)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("EvalPredicate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if got != tt.want {
+				t.Errorf("EvalPredicate() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEvalValue(t *testing.T) {
+	tests := []struct {
+		expr    string
+		want    string
+		wantErr bool
+	}{
+		{expr: "'postgres://' + string(data.username) + ':' + string(data.password) + '@db'", want: "postgres://admin:secret@db"},
+		{expr: "data.password", want: "secret"},
+		{expr: "base64.encode(data.username)", want: "YWRtaW4="},
+		{expr: "name.upperAscii()", want: "DB"},
+		{expr: "srcNamespace + '/' + name", want: "prod/db"},
+		{expr: "string(data.token)", wantErr: true},
+		{expr: "'password' in data", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			got, err := EvalValue(tt.expr, testSource)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("EvalValue() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if string(got) != tt.want {
+				t.Errorf("EvalValue() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEvalCostLimit(t *testing.T) {
+	// Every comprehension level multiplies the cost of the nested one
+	expr := "[" + strings.Repeat("1,", 99) + "1]" +
+		".all(a, [" + strings.Repeat("1,", 99) + "1]" +
+		".all(b, [" + strings.Repeat("1,", 99) + "1].all(c, a + b + c > 0)))"
+
+	if _, err := EvalPredicate(expr, testSource); err == nil || !strings.Contains(err.Error(), "cost limit") {
+		t.Errorf("EvalPredicate() error = %v, want the cost limit exceeded", err)
+	}
+}
+
+func TestCheckDoesNotCache(t *testing.T) {
+	before := programs.order.Len()
+
+	for i := 0; i < 10; i++ {
+		_ = CheckPredicate(fmt.Sprintf("name == 'rejected-%d' && 1", i))
+		_ = CheckPredicate(fmt.Sprintf("name == 'checked-%d'", i))
+	}
+
+	if programs.order.Len() != before {
+		t.Errorf("cache grew from %d to %d programs on the checks", before, programs.order.Len())
+	}
+}
+
+func TestProgramCacheEvictsLeastRecentlyUsed(t *testing.T) {
+	cache := newProgramCache(2)
+
+	first, err := compile("name == 'first'", cel.BoolType)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cache.add("first", first)
+	cache.add("second", first)
+
+	// Reading the first entry makes the second one the least recently used
+	if _, ok := cache.get("first"); !ok {
+		t.Fatal("first entry is missing")
+	}
+
+	cache.add("third", first)
+
+	if cache.order.Len() != 2 {
+		t.Errorf("cache holds %d programs, want 2", cache.order.Len())
+	}
+
+	if _, ok := cache.get("second"); ok {
+		t.Error("least recently used entry was not evicted")
+	}
+
+	for _, key := range []string{"first", "third"} {
+		if _, ok := cache.get(key); !ok {
+			t.Errorf("entry %s was evicted", key)
+		}
+	}
+}
@@ -0,0 +1,116 @@
+/*
+Copyright 2025 Edenlab
+*/
+
+package keystore
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/x509"
+
+	jks "github.com/pavlo-v-chernykh/keystore-go/v4"
+)
+
+const jksCertType = "X.509"
+
+// JKS encodes the private key with its certificate chain under the alias,
+// the key and the keystore integrity are protected with the password
+func JKS(key crypto.PrivateKey, chain []*x509.Certificate, alias, password string) ([]byte, error) {
+	keyDER, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		return nil, err
+	}
+
+	entry := jks.PrivateKeyEntry{CreationTime: chain[0].NotBefore, PrivateKey: keyDER}
+	for _, cert := range chain {
+		entry.CertificateChain = append(entry.CertificateChain, jks.Certificate{Type: jksCertType, Content: cert.Raw})
+	}
+
+	ks := jks.New(jks.WithOrderedAliases())
+	if err := ks.SetPrivateKeyEntry(alias, entry, []byte(password)); err != nil {
+		return nil, err
+	}
+
+	return storeJKS(ks, password)
+}
+
+// JKSTruststore encodes the trusted certificates, the keystore integrity is protected with the password
+func JKSTruststore(certs []*x509.Certificate, password string) ([]byte, error) {
+	ks := jks.New(jks.WithOrderedAliases())
+
+	aliases := trustedAliases(certs)
+	for i, cert := range certs {
+		entry := jks.TrustedCertificateEntry{
+			CreationTime: cert.NotBefore,
+			Certificate:  jks.Certificate{Type: jksCertType, Content: cert.Raw},
+		}
+
+		if err := ks.SetTrustedCertificateEntry(aliases[i], entry); err != nil {
+			return nil, err
+		}
+	}
+
+	return storeJKS(ks, password)
+}
+
+// JKSEqual reports whether both keystores or truststores hold the same entries under the same aliases,
+// keystores which cannot be decoded with the password are never equal
+func JKSEqual(a, b []byte, password string) bool {
+	first, err := jksContent(a, password)
+	if err != nil {
+		return false
+	}
+
+	second, err := jksContent(b, password)
+	if err != nil {
+		return false
+	}
+
+	return contentEqual(first, second)
+}
+
+// jksContent returns the aliases of the entries, each followed by its private key and certificates
+func jksContent(data []byte, password string) ([][]byte, error) {
+	ks := jks.New(jks.WithOrderedAliases())
+	if err := ks.Load(bytes.NewReader(data), []byte(password)); err != nil {
+		return nil, err
+	}
+
+	var content [][]byte
+	for _, alias := range ks.Aliases() {
+		content = append(content, []byte(alias))
+
+		if !ks.IsPrivateKeyEntry(alias) {
+			entry, err := ks.GetTrustedCertificateEntry(alias)
+			if err != nil {
+				return nil, err
+			}
+
+			content = append(content, entry.Certificate.Content)
+			continue
+		}
+
+		entry, err := ks.GetPrivateKeyEntry(alias, []byte(password))
+		if err != nil {
+			return nil, err
+		}
+
+		content = append(content, entry.PrivateKey)
+		for _, cert := range entry.CertificateChain {
+			content = append(content, cert.Content)
+		}
+	}
+
+	return content, nil
+}
+
+// storeJKS signs the keystore with the password
+func storeJKS(ks jks.KeyStore, password string) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := ks.Store(&buf, []byte(password)); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,101 @@
+/*
+Copyright 2025 Edenlab
+*/
+
+package keystore
+
+import (
+	"bytes"
+	"crypto/x509"
+	"testing"
+
+	jks "github.com/pavlo-v-chernykh/keystore-go/v4"
+)
+
+// loadJKS decodes the keystore and verifies its integrity with the password
+func loadJKS(t *testing.T, data []byte, password string) jks.KeyStore {
+	t.Helper()
+
+	ks := jks.New(jks.WithOrderedAliases())
+	if err := ks.Load(bytes.NewReader(data), []byte(password)); err != nil {
+		t.Fatal(err)
+	}
+
+	return ks
+}
+
+func TestJKSRoundTrip(t *testing.T) {
+	key, chain := newTestChain(t)
+
+	data, err := JKS(key, chain, "App", "changeit")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ks := loadJKS(t, data, "changeit")
+	if aliases := ks.Aliases(); len(aliases) != 1 || aliases[0] != "app" {
+		t.Fatalf("got the aliases %v, want the private key entry app", aliases)
+	}
+
+	entry, err := ks.GetPrivateKeyEntry("app", []byte("changeit"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !entry.CreationTime.Equal(chain[0].NotBefore) {
+		t.Errorf("entry date = %v, want %v", entry.CreationTime, chain[0].NotBefore)
+	}
+
+	decodedKey, err := x509.ParsePKCS8PrivateKey(entry.PrivateKey)
+	if err != nil {
+		t.Fatalf("key: %v", err)
+	}
+
+	if !key.Equal(decodedKey) {
+		t.Error("decoded key differs from the encoded one")
+	}
+
+	if len(entry.CertificateChain) != len(chain) {
+		t.Fatalf("got %d certificates, want %d", len(entry.CertificateChain), len(chain))
+	}
+
+	for i, cert := range chain {
+		if entry.CertificateChain[i].Type != jksCertType || !bytes.Equal(entry.CertificateChain[i].Content, cert.Raw) {
+			t.Errorf("certificate %d differs from the chain", i)
+		}
+	}
+}
+
+func TestJKSTruststore(t *testing.T) {
+	_, chain := newTestChain(t)
+
+	data, err := JKSTruststore(chain, "changeit")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ks := loadJKS(t, data, "changeit")
+	for i, alias := range []string{"app", "test ca"} {
+		entry, err := ks.GetTrustedCertificateEntry(alias)
+		if err != nil {
+			t.Fatalf("trusted certificate %s: %v", alias, err)
+		}
+
+		if !bytes.Equal(entry.Certificate.Content, chain[i].Raw) {
+			t.Errorf("certificate %s differs", alias)
+		}
+	}
+}
+
+func TestJKSWrongPassword(t *testing.T) {
+	key, chain := newTestChain(t)
+
+	data, err := JKS(key, chain, "app", "changeit")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := jks.New().Load(bytes.NewReader(data), []byte("wrong")); err == nil {
+		t.Error("integrity digest verified with a wrong password")
+	}
+}
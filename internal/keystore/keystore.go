@@ -0,0 +1,105 @@
+/*
+Copyright 2025 Edenlab
+*/
+
+// Package keystore encodes private keys and certificates to the PKCS#12 and JKS keystores
+// read by Java applications.
+//
+// The keystores are encoded with random salts, so the same entries never produce identical keystores.
+// The Equal functions compare the decoded entries instead, letting unchanged keystores be kept as they are.
+package keystore
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"strings"
+)
+
+// ParseCertificates parses all PEM encoded certificates
+func ParseCertificates(data []byte) ([]*x509.Certificate, error) {
+	var certs []*x509.Certificate
+
+	for block, rest := pem.Decode(data); block != nil; block, rest = pem.Decode(rest) {
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+
+		certs = append(certs, cert)
+	}
+
+	if len(certs) == 0 {
+		return nil, fmt.Errorf("no PEM certificate found")
+	}
+
+	return certs, nil
+}
+
+// ParsePrivateKey parses the first PEM encoded PKCS#1, PKCS#8 or EC private key
+func ParsePrivateKey(data []byte) (crypto.PrivateKey, error) {
+	for block, rest := pem.Decode(data); block != nil; block, rest = pem.Decode(rest) {
+		switch block.Type {
+		case "RSA PRIVATE KEY":
+			return x509.ParsePKCS1PrivateKey(block.Bytes)
+		case "EC PRIVATE KEY":
+			return x509.ParseECPrivateKey(block.Bytes)
+		case "PRIVATE KEY":
+			return x509.ParsePKCS8PrivateKey(block.Bytes)
+		}
+	}
+
+	return nil, fmt.Errorf("no PEM private key found")
+}
+
+// trustedAliases returns unique names of the trusted certificate entries
+func trustedAliases(certs []*x509.Certificate) []string {
+	var aliases []string
+
+	seen := make(map[string]int)
+	for _, cert := range certs {
+		name := strings.ToLower(cert.Subject.CommonName)
+		if len(name) == 0 {
+			name = cert.SerialNumber.String()
+		}
+
+		if seen[name]++; seen[name] > 1 {
+			name = fmt.Sprintf("%s-%d", name, seen[name]-1)
+		}
+
+		aliases = append(aliases, name)
+	}
+
+	return aliases
+}
+
+// rawCertificates returns the DER encodings of the certificates
+func rawCertificates(certs []*x509.Certificate) [][]byte {
+	raw := make([][]byte, 0, len(certs))
+	for _, cert := range certs {
+		raw = append(raw, cert.Raw)
+	}
+
+	return raw
+}
+
+// contentEqual reports whether the decoded entries are the same
+func contentEqual(a, b [][]byte) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	for i := range a {
+		if !bytes.Equal(a[i], b[i]) {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,144 @@
+/*
+Copyright 2025 Edenlab
+*/
+
+package keystore
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"math/big"
+	"testing"
+	"time"
+)
+
+// newTestChain returns a key with its certificate signed by a new CA and the CA certificate
+func newTestChain(t *testing.T) (*ecdsa.PrivateKey, []*x509.Certificate) {
+	t.Helper()
+
+	caKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	notBefore := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	caTemplate := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "Test CA"},
+		NotBefore:             notBefore,
+		NotAfter:              notBefore.AddDate(1, 0, 0),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	}
+
+	caDER, err := x509.CreateCertificate(rand.Reader, caTemplate, caTemplate, &caKey.PublicKey, caKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ca, err := x509.ParseCertificate(caDER)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(2),
+		Subject:      pkix.Name{CommonName: "app"},
+		NotBefore:    notBefore,
+		NotAfter:     notBefore.AddDate(1, 0, 0),
+		DNSNames:     []string{"app.default.svc"},
+	}
+
+	certDER, err := x509.CreateCertificate(rand.Reader, template, ca, &key.PublicKey, caKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cert, err := x509.ParseCertificate(certDER)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return key, []*x509.Certificate{cert, ca}
+}
+
+func TestEqual(t *testing.T) {
+	key, chain := newTestChain(t)
+
+	otherKey, otherChain := newTestChain(t)
+
+	tests := []struct {
+		name   string
+		encode func(key crypto.PrivateKey, chain []*x509.Certificate, password string) ([]byte, error)
+		equal  func(a, b []byte, password string) bool
+	}{
+		{
+			name: "pkcs12",
+			encode: func(key crypto.PrivateKey, chain []*x509.Certificate, password string) ([]byte, error) {
+				return PKCS12(key, chain, password)
+			},
+			equal: PKCS12Equal,
+		},
+		{
+			name: "pkcs12 truststore",
+			encode: func(_ crypto.PrivateKey, chain []*x509.Certificate, password string) ([]byte, error) {
+				return PKCS12Truststore(chain[1:], password)
+			},
+			equal: PKCS12Equal,
+		},
+		{
+			name: "jks",
+			encode: func(key crypto.PrivateKey, chain []*x509.Certificate, password string) ([]byte, error) {
+				return JKS(key, chain, "app", password)
+			},
+			equal: JKSEqual,
+		},
+		{
+			name: "jks truststore",
+			encode: func(_ crypto.PrivateKey, chain []*x509.Certificate, password string) ([]byte, error) {
+				return JKSTruststore(chain[1:], password)
+			},
+			equal: JKSEqual,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			encode := func(key crypto.PrivateKey, chain []*x509.Certificate, password string) []byte {
+				data, err := tt.encode(key, chain, password)
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				return data
+			}
+
+			first, second := encode(key, chain, "changeit"), encode(key, chain, "changeit")
+			if !tt.equal(first, second, "changeit") {
+				t.Error("keystores of the same entries are not equal")
+			}
+
+			if tt.equal(first, encode(otherKey, otherChain, "changeit"), "changeit") {
+				t.Error("keystores of other entries are equal")
+			}
+
+			if tt.equal(first, encode(key, chain, "rotated"), "changeit") {
+				t.Error("keystore encrypted with another password is equal")
+			}
+
+			if tt.equal(first, []byte("corrupted"), "changeit") {
+				t.Error("corrupted keystore is equal")
+			}
+		})
+	}
+}
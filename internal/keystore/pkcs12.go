@@ -0,0 +1,68 @@
+/*
+Copyright 2025 Edenlab
+*/
+
+package keystore
+
+import (
+	"crypto"
+	"crypto/x509"
+
+	"software.sslmate.com/src/go-pkcs12"
+)
+
+// PKCS12 encodes the private key with its certificate chain, the key and the certificates are encrypted
+// with the password. The 3DES encryption is read by every Java version and by OpenSSL 3.
+// The key entry has no friendly name, Java lists it under a generated alias.
+func PKCS12(key crypto.PrivateKey, chain []*x509.Certificate, password string) ([]byte, error) {
+	return pkcs12.LegacyDES.Encode(key, chain[0], chain[1:], password)
+}
+
+// PKCS12Truststore encodes the trusted certificates, Java only reads the certificates
+// marked with the trusted key usage attribute which the encoder adds to every entry
+func PKCS12Truststore(certs []*x509.Certificate, password string) ([]byte, error) {
+	aliases := trustedAliases(certs)
+
+	entries := make([]pkcs12.TrustStoreEntry, 0, len(certs))
+	for i, cert := range certs {
+		entries = append(entries, pkcs12.TrustStoreEntry{Cert: cert, FriendlyName: aliases[i]})
+	}
+
+	return pkcs12.LegacyDES.EncodeTrustStoreEntries(entries, password)
+}
+
+// PKCS12Equal reports whether both keystores or truststores hold the same key and certificates,
+// keystores which cannot be decoded with the password are never equal
+func PKCS12Equal(a, b []byte, password string) bool {
+	first, err := pkcs12Content(a, password)
+	if err != nil {
+		return false
+	}
+
+	second, err := pkcs12Content(b, password)
+	if err != nil {
+		return false
+	}
+
+	return contentEqual(first, second)
+}
+
+// pkcs12Content returns the private key followed by its certificate chain, or the trusted certificates
+func pkcs12Content(data []byte, password string) ([][]byte, error) {
+	key, cert, caCerts, err := pkcs12.DecodeChain(data, password)
+	if err != nil {
+		certs, trustErr := pkcs12.DecodeTrustStore(data, password)
+		if trustErr != nil {
+			return nil, err
+		}
+
+		return rawCertificates(certs), nil
+	}
+
+	keyDER, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return append([][]byte{keyDER}, rawCertificates(append([]*x509.Certificate{cert}, caCerts...))...), nil
+}
@@ -0,0 +1,132 @@
+/*
+Copyright 2025 Edenlab
+*/
+
+package keystore
+
+import (
+	"bytes"
+	"crypto/x509"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"software.sslmate.com/src/go-pkcs12"
+)
+
+func TestPKCS12RoundTrip(t *testing.T) {
+	key, chain := newTestChain(t)
+
+	data, err := PKCS12(key, chain, "changeit")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	decodedKey, cert, caCerts, err := pkcs12.DecodeChain(data, "changeit")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !key.Equal(decodedKey) {
+		t.Error("decoded key differs from the encoded one")
+	}
+
+	decodedChain := append([]*x509.Certificate{cert}, caCerts...)
+	if len(decodedChain) != len(chain) {
+		t.Fatalf("got %d certificates, want %d", len(decodedChain), len(chain))
+	}
+
+	for i, cert := range chain {
+		if !bytes.Equal(decodedChain[i].Raw, cert.Raw) {
+			t.Errorf("certificate %d differs from the chain", i)
+		}
+	}
+}
+
+func TestPKCS12Truststore(t *testing.T) {
+	_, chain := newTestChain(t)
+
+	data, err := PKCS12Truststore([]*x509.Certificate{chain[1], chain[1]}, "changeit")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The trust store decoder rejects the certificates without the trusted key usage attribute
+	certs, err := pkcs12.DecodeTrustStore(data, "changeit")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(certs) != 2 {
+		t.Fatalf("got %d certificates, want 2", len(certs))
+	}
+
+	for i, cert := range certs {
+		if !bytes.Equal(cert.Raw, chain[1].Raw) {
+			t.Errorf("certificate %d differs from the CA", i)
+		}
+	}
+}
+
+func TestPKCS12WrongPassword(t *testing.T) {
+	key, chain := newTestChain(t)
+
+	data, err := PKCS12(key, chain, "changeit")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, _, _, err := pkcs12.DecodeChain(data, "wrong"); err == nil {
+		t.Error("keystore decoded with a wrong password")
+	}
+}
+
+// TestPKCS12OpenSSL reads the keystore and the truststore with openssl when it is installed
+func TestPKCS12OpenSSL(t *testing.T) {
+	if _, err := exec.LookPath("openssl"); err != nil {
+		t.Skip("openssl is not installed")
+	}
+
+	key, chain := newTestChain(t)
+
+	keystore, err := PKCS12(key, chain, "changeit")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	truststore, err := PKCS12Truststore(chain[1:], "changeit")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name string
+		data []byte
+		want []string
+	}{
+		{name: "keystore", data: keystore, want: []string{"BEGIN PRIVATE KEY", "subject=CN = app", "subject=CN = Test CA"}},
+		{name: "truststore", data: truststore, want: []string{"friendlyName: test ca", "subject=CN = Test CA"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), tt.name+".p12")
+			if err := os.WriteFile(path, tt.data, 0o600); err != nil {
+				t.Fatal(err)
+			}
+
+			out, err := exec.Command("openssl", "pkcs12", "-in", path, "-passin", "pass:changeit", "-nodes").CombinedOutput()
+			if err != nil {
+				t.Fatalf("openssl pkcs12: %v\n%s", err, out)
+			}
+
+			for _, want := range tt.want {
+				if !strings.Contains(string(out), want) {
+					t.Errorf("openssl output does not contain %q:\n%s", want, out)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,271 @@
+/*
+Copyright 2025 Edenlab
+*/
+
+// Package naming renders the templates of the destination names, e.g. {{.SourceNamespace}}-{{.SourceName}}.
+//
+// The templates are not Go templates: an action is a single field optionally piped through the whitelisted
+// functions with quoted arguments, e.g. {{.SourceName | trimPrefix "app-" | upper}}. Every intermediate value is
+// limited to the length of a name, so that a template can not make the controller allocate unbounded memory.
+package naming
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+const (
+	actionStart = "{{"
+	actionEnd   = "}}"
+)
+
+// Vars are the fields available to the name templates
+type Vars struct {
+	// SourceName and SourceNamespace identify the source object
+	SourceName      string
+	SourceNamespace string
+	// SourceKind is Secret or ConfigMap
+	SourceKind string
+	// Namespace is the namespace the destination is created in
+	Namespace string
+}
+
+// field returns the value of the field of the vars
+func (v Vars) field(name string) string {
+	switch name {
+	case ".SourceName":
+		return v.SourceName
+	case ".SourceNamespace":
+		return v.SourceNamespace
+	case ".SourceKind":
+		return v.SourceKind
+	default:
+		return v.Namespace
+	}
+}
+
+var fields = map[string]bool{".SourceName": true, ".SourceNamespace": true, ".SourceKind": true, ".Namespace": true}
+
+// function is a whitelisted function applied to the piped value with the fixed number of arguments
+type function struct {
+	args  int
+	apply func(val string, args []string) string
+}
+
+var funcs = map[string]function{
+	"lower":      {args: 0, apply: func(val string, _ []string) string { return strings.ToLower(val) }},
+	"upper":      {args: 0, apply: func(val string, _ []string) string { return strings.ToUpper(val) }},
+	"replace":    {args: 2, apply: func(val string, args []string) string { return strings.ReplaceAll(val, args[0], args[1]) }},
+	"trimPrefix": {args: 1, apply: func(val string, args []string) string { return strings.TrimPrefix(val, args[0]) }},
+	"trimSuffix": {args: 1, apply: func(val string, args []string) string { return strings.TrimSuffix(val, args[0]) }},
+}
+
+// segment is either a literal text or an action
+type segment struct {
+	text   string
+	action *action
+}
+
+// action is a field piped through the functions
+type action struct {
+	field string
+	calls []call
+}
+
+type call struct {
+	name string
+	args []string
+}
+
+type token struct {
+	val    string
+	quoted bool
+}
+
+// IsTemplate reports whether the name has to be rendered
+func IsTemplate(name string) bool {
+	return strings.Contains(name, actionStart)
+}
+
+// Parse checks the syntax of the name template
+func Parse(name string) error {
+	_, err := parse(name)
+	return err
+}
+
+// Render substitutes the actions of the name template and validates the result as a DNS-1123 subdomain
+func Render(name string, vars Vars) (string, error) {
+	segments, err := parse(name)
+	if err != nil {
+		return "", err
+	}
+
+	var rendered strings.Builder
+	for _, s := range segments {
+		if s.action == nil {
+			rendered.WriteString(s.text)
+			continue
+		}
+
+		val, err := s.action.render(vars)
+		if err != nil {
+			return "", err
+		}
+
+		rendered.WriteString(val)
+	}
+
+	if errs := validation.IsDNS1123Subdomain(rendered.String()); len(errs) > 0 {
+		return "", fmt.Errorf("invalid name %q: %s", rendered.String(), strings.Join(errs, ", "))
+	}
+
+	return rendered.String(), nil
+}
+
+// render applies the functions to the field, the values longer than a name are rejected
+func (a *action) render(vars Vars) (string, error) {
+	val := vars.field(a.field)
+
+	for _, c := range a.calls {
+		if val = funcs[c.name].apply(val, c.args); len(val) > validation.DNS1123SubdomainMaxLength {
+			return "", fmt.Errorf("%s of %s is longer than %d characters", c.name, a.field, validation.DNS1123SubdomainMaxLength)
+		}
+	}
+
+	return val, nil
+}
+
+// parse splits the template into the literal texts and the actions
+func parse(name string) ([]segment, error) {
+	var segments []segment
+
+	for rest := name; len(rest) > 0; {
+		start := strings.Index(rest, actionStart)
+		if start < 0 {
+			segments = append(segments, segment{text: rest})
+			break
+		}
+
+		if start > 0 {
+			segments = append(segments, segment{text: rest[:start]})
+		}
+
+		rest = rest[start+len(actionStart):]
+
+		end := strings.Index(rest, actionEnd)
+		if end < 0 {
+			return nil, fmt.Errorf("unclosed action in %q", name)
+		}
+
+		a, err := parseAction(rest[:end])
+		if err != nil {
+			return nil, fmt.Errorf("action {{%s}}: %w", rest[:end], err)
+		}
+
+		segments = append(segments, segment{action: a})
+		rest = rest[end+len(actionEnd):]
+	}
+
+	return segments, nil
+}
+
+// parseAction parses a field followed by the piped function calls
+func parseAction(text string) (*action, error) {
+	tokens, err := tokenize(text)
+	if err != nil {
+		return nil, err
+	}
+
+	commands := [][]token{nil}
+	for _, t := range tokens {
+		if !t.quoted && t.val == "|" {
+			commands = append(commands, nil)
+			continue
+		}
+
+		commands[len(commands)-1] = append(commands[len(commands)-1], t)
+	}
+
+	first := commands[0]
+	if len(first) != 1 || first[0].quoted || !fields[first[0].val] {
+		return nil, fmt.Errorf("an action has to start with one of the fields .SourceName, .SourceNamespace, " +
+			".SourceKind or .Namespace")
+	}
+
+	a := &action{field: first[0].val}
+	for _, command := range commands[1:] {
+		if len(command) == 0 || command[0].quoted {
+			return nil, fmt.Errorf("missing function after |")
+		}
+
+		fn, ok := funcs[command[0].val]
+		if !ok {
+			return nil, fmt.Errorf("unknown function %s, allowed are lower, upper, replace, trimPrefix and trimSuffix",
+				command[0].val)
+		}
+
+		if len(command)-1 != fn.args {
+			return nil, fmt.Errorf("function %s takes %d arguments", command[0].val, fn.args)
+		}
+
+		c := call{name: command[0].val}
+		for _, arg := range command[1:] {
+			if !arg.quoted {
+				return nil, fmt.Errorf("argument %s of function %s is not a quoted string", arg.val, c.name)
+			}
+
+			c.args = append(c.args, arg.val)
+		}
+
+		a.calls = append(a.calls, c)
+	}
+
+	return a, nil
+}
+
+// tokenize splits the action into the words, the pipes and the unquoted strings
+func tokenize(text string) ([]token, error) {
+	var tokens []token
+
+	for i := 0; i < len(text); {
+		switch c := text[i]; {
+		case c == ' ' || c == '\t':
+			i++
+		case c == '|':
+			tokens = append(tokens, token{val: "|"})
+			i++
+		case c == '"':
+			end := i + 1
+			for ; end < len(text) && text[end] != '"'; end++ {
+				if text[end] == '\\' {
+					end++
+				}
+			}
+
+			if end >= len(text) {
+				return nil, fmt.Errorf("unterminated string")
+			}
+
+			val, err := strconv.Unquote(text[i : end+1])
+			if err != nil {
+				return nil, fmt.Errorf("string %s: %w", text[i:end+1], err)
+			}
+
+			tokens = append(tokens, token{val: val, quoted: true})
+			i = end + 1
+		default:
+			end := i
+			for end < len(text) && !strings.ContainsRune(" \t|\"", rune(text[end])) {
+				end++
+			}
+
+			tokens = append(tokens, token{val: text[i:end]})
+			i = end
+		}
+	}
+
+	return tokens, nil
+}
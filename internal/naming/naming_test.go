@@ -0,0 +1,77 @@
+/*
+Copyright 2025 Edenlab
+*/
+
+package naming
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRender(t *testing.T) {
+	vars := Vars{SourceName: "app-db", SourceNamespace: "Team", SourceKind: "Secret", Namespace: "prod"}
+
+	tests := []struct {
+		name    string
+		tmpl    string
+		want    string
+		wantErr bool
+	}{
+		{name: "plain name", tmpl: "app", want: "app"},
+		{name: "fields", tmpl: "{{.SourceName}}-{{.Namespace}}", want: "app-db-prod"},
+		{name: "spaces", tmpl: "{{ .SourceName }}", want: "app-db"},
+		{name: "lower", tmpl: "{{.SourceNamespace | lower}}-{{.SourceKind | lower}}", want: "team-secret"},
+		{name: "pipeline", tmpl: `{{.SourceName | trimPrefix "app-" | replace "db" "postgres"}}`, want: "postgres"},
+		{name: "trim suffix", tmpl: `{{.SourceName | trimSuffix "-db"}}-copy`, want: "app-copy"},
+		{name: "escaped quote", tmpl: `{{.SourceName | replace "\"" "x"}}`, want: "app-db"},
+		{name: "invalid rendered name", tmpl: "{{.SourceNamespace}}", wantErr: true},
+		{name: "upper rendered name", tmpl: "{{.SourceName | upper}}", wantErr: true},
+		{name: "unknown field", tmpl: "{{.Secret}}", wantErr: true},
+		{name: "unknown function", tmpl: "{{.SourceName | title}}", wantErr: true},
+		{name: "printf", tmpl: `{{printf "%0999999d" 0}}`, wantErr: true},
+		{name: "piped printf", tmpl: `{{.SourceName | printf "%0999999d"}}`, wantErr: true},
+		{name: "range", tmpl: "{{range .SourceName}}x{{end}}", wantErr: true},
+		{name: "variable", tmpl: "{{$x := .SourceName}}", wantErr: true},
+		{name: "function without field", tmpl: `{{lower "APP"}}`, wantErr: true},
+		{name: "unquoted argument", tmpl: "{{.SourceName | trimPrefix app}}", wantErr: true},
+		{name: "missing argument", tmpl: `{{.SourceName | replace "a"}}`, wantErr: true},
+		{name: "empty pipe", tmpl: "{{.SourceName |}}", wantErr: true},
+		{name: "unclosed action", tmpl: "{{.SourceName", wantErr: true},
+		{name: "unterminated string", tmpl: `{{.SourceName | trimPrefix "app}}`, wantErr: true},
+		{name: "empty action", tmpl: "{{}}", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Render(tt.tmpl, vars)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Render() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if got != tt.want {
+				t.Errorf("Render() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRenderLimitsIntermediateValues(t *testing.T) {
+	vars := Vars{SourceName: strings.Repeat("a", 200)}
+
+	// Every replace multiplies the length, the second one would need gigabytes without the limit
+	tmpl := `{{.SourceName | replace "" "` + strings.Repeat("b", 200) + `" | replace "" "` + strings.Repeat("c", 200) + `"}}`
+	if _, err := Render(tmpl, vars); err == nil || !strings.Contains(err.Error(), "longer than") {
+		t.Errorf("Render() error = %v, want the length limit", err)
+	}
+}
+
+func TestParse(t *testing.T) {
+	if err := Parse(`{{.Namespace}}-{{.SourceName | lower | trimSuffix "-tls"}}`); err != nil {
+		t.Errorf("Parse() error = %v", err)
+	}
+
+	if err := Parse(`{{template "x"}}`); err == nil {
+		t.Error("Parse() accepted a template action")
+	}
+}
@@ -0,0 +1,221 @@
+/*
+Copyright 2025 Edenlab
+*/
+
+package transformer
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf16"
+	"unicode/utf8"
+
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+const (
+	Base64Decode = "base64Decode"
+	Base64Encode = "base64Encode"
+	TrimSpace    = "trimSpace"
+	HexEncode    = "hexEncode"
+	HexDecode    = "hexDecode"
+	Bundle       = "bundle"
+
+	// ParamKeys is the comma separated list of the keys a transformer applies to, all keys when it is empty
+	ParamKeys = "keys"
+	// ParamKey is the file key of the bundle
+	ParamKey = "key"
+	// ParamFormat is the format of the bundle: env, json or properties
+	ParamFormat = "format"
+	// ParamKeepKeys keeps the bundled keys next to the bundle when it is true
+	ParamKeepKeys = "keepKeys"
+
+	bundleFormatEnv        = "env"
+	bundleFormatJSON       = "json"
+	bundleFormatProperties = "properties"
+)
+
+func init() {
+	Register(Base64Decode, perKey(func(val []byte) ([]byte, error) {
+		// Values copied from the manifests often keep the trailing newline of the encoded value
+		return base64.StdEncoding.DecodeString(strings.TrimSpace(string(val)))
+	}))
+	Register(Base64Encode, perKey(func(val []byte) ([]byte, error) {
+		return []byte(base64.StdEncoding.EncodeToString(val)), nil
+	}))
+	Register(TrimSpace, perKey(func(val []byte) ([]byte, error) {
+		return bytes.TrimSpace(val), nil
+	}))
+	Register(HexEncode, perKey(func(val []byte) ([]byte, error) {
+		return []byte(hex.EncodeToString(val)), nil
+	}))
+	Register(HexDecode, perKey(func(val []byte) ([]byte, error) {
+		return hex.DecodeString(strings.TrimSpace(string(val)))
+	}))
+	Register(Bundle, Func(bundle))
+}
+
+// perKey returns a transformer applying the function to the values of the keys param
+func perKey(fn func(val []byte) ([]byte, error)) Transformer {
+	return Func(func(data map[string][]byte, params map[string]string) (map[string][]byte, error) {
+		keys, err := selectKeys(data, params[ParamKeys])
+		if err != nil {
+			return nil, err
+		}
+
+		for _, key := range keys {
+			val, err := fn(data[key])
+			if err != nil {
+				return nil, fmt.Errorf("key %s: %w", key, err)
+			}
+
+			data[key] = val
+		}
+
+		return data, nil
+	})
+}
+
+// bundle writes the selected keys into the file key, the bundled keys are removed unless they are kept
+func bundle(data map[string][]byte, params map[string]string) (map[string][]byte, error) {
+	var (
+		buf    bytes.Buffer
+		values = make(map[string]string)
+	)
+
+	bundleKey := params[ParamKey]
+	if len(bundleKey) == 0 {
+		return nil, fmt.Errorf("param %s is required", ParamKey)
+	}
+
+	keepKeys := false
+	if len(params[ParamKeepKeys]) > 0 {
+		var err error
+		if keepKeys, err = strconv.ParseBool(params[ParamKeepKeys]); err != nil {
+			return nil, fmt.Errorf("param %s: %w", ParamKeepKeys, err)
+		}
+	}
+
+	keys, err := selectKeys(data, params[ParamKeys])
+	if err != nil {
+		return nil, err
+	}
+
+	for _, key := range keys {
+		val := data[key]
+		if !utf8.Valid(val) {
+			return nil, fmt.Errorf("key %s is not valid UTF-8", key)
+		}
+
+		switch params[ParamFormat] {
+		case bundleFormatEnv:
+			if errs := validation.IsEnvVarName(key); len(errs) > 0 {
+				return nil, fmt.Errorf("key %s is not a valid variable name: %s", key, strings.Join(errs, ", "))
+			}
+
+			fmt.Fprintf(&buf, "%s=%s\n", key, envValue(string(val)))
+		case bundleFormatProperties:
+			fmt.Fprintf(&buf, "%s=%s\n", propertiesEscape(key, true), propertiesEscape(string(val), false))
+		case bundleFormatJSON:
+			values[key] = string(val)
+		default:
+			return nil, fmt.Errorf("unknown format %q", params[ParamFormat])
+		}
+	}
+
+	if params[ParamFormat] == bundleFormatJSON {
+		encoded, err := json.MarshalIndent(values, "", "  ")
+		if err != nil {
+			return nil, err
+		}
+
+		buf.Write(append(encoded, '\n'))
+	}
+
+	if !keepKeys {
+		for _, key := range keys {
+			delete(data, key)
+		}
+	}
+
+	data[bundleKey] = buf.Bytes()
+
+	return data, nil
+}
+
+// selectKeys returns the sorted keys of the comma separated list, all keys of the data when it is empty
+func selectKeys(data map[string][]byte, list string) ([]string, error) {
+	var keys []string
+
+	for _, key := range strings.Split(list, ",") {
+		if key = strings.TrimSpace(key); len(key) == 0 {
+			continue
+		}
+
+		if _, ok := data[key]; !ok {
+			return nil, fmt.Errorf("key %s not found", key)
+		}
+
+		keys = append(keys, key)
+	}
+
+	if len(keys) == 0 {
+		for key := range data {
+			keys = append(keys, key)
+		}
+	}
+
+	sort.Strings(keys)
+
+	return keys, nil
+}
+
+// envValue quotes the values which are not read literally by the shell and dotenv parsers
+func envValue(val string) string {
+	if len(val) > 0 && !strings.ContainsAny(val, " \t\r\n\"'\\$#`=") {
+		return val
+	}
+
+	replacer := strings.NewReplacer(`\`, `\\`, `"`, `\"`, "$", `\$`, "`", "\\`", "\n", `\n`, "\r", `\r`)
+
+	return `"` + replacer.Replace(val) + `"`
+}
+
+// propertiesEscape escapes the key or the value as read by java.util.Properties
+func propertiesEscape(val string, key bool) string {
+	var buf strings.Builder
+
+	for i, r := range val {
+		switch {
+		case r == '\\':
+			buf.WriteString(`\\`)
+		case r == '\n':
+			buf.WriteString(`\n`)
+		case r == '\r':
+			buf.WriteString(`\r`)
+		case r == '\t':
+			buf.WriteString(`\t`)
+		case r == '\f':
+			buf.WriteString(`\f`)
+		case r == ' ' && (key || i == 0):
+			buf.WriteString(`\ `)
+		case strings.ContainsRune("=:#!", r):
+			buf.WriteRune('\\')
+			buf.WriteRune(r)
+		case r < 0x20 || r > 0x7e:
+			for _, unit := range utf16.Encode([]rune{r}) {
+				fmt.Fprintf(&buf, "\\u%04X", unit)
+			}
+		default:
+			buf.WriteRune(r)
+		}
+	}
+
+	return buf.String()
+}
@@ -0,0 +1,170 @@
+/*
+Copyright 2025 Edenlab
+*/
+
+package transformer
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestEnvValue(t *testing.T) {
+	tests := []struct {
+		val  string
+		want string
+	}{
+		{val: "plain", want: "plain"},
+		{val: "", want: `""`},
+		{val: "with space", want: `"with space"`},
+		{val: "tab\tvalue", want: "\"tab\tvalue\""},
+		{val: `say "hi"`, want: `"say \"hi\""`},
+		{val: "it's", want: `"it's"`},
+		{val: `C:\path`, want: `"C:\\path"`},
+		{val: "$HOME", want: `"\$HOME"`},
+		{val: "`id`", want: "\"\\`id\\`\""},
+		{val: "line1\nline2", want: `"line1\nline2"`},
+		{val: "crlf\r\n", want: `"crlf\r\n"`},
+		{val: "#comment", want: `"#comment"`},
+		{val: "a=b", want: `"a=b"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.val, func(t *testing.T) {
+			if got := envValue(tt.val); got != tt.want {
+				t.Errorf("envValue(%q) = %s, want %s", tt.val, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPropertiesEscape(t *testing.T) {
+	tests := []struct {
+		val  string
+		key  bool
+		want string
+	}{
+		{val: "plain", want: "plain"},
+		{val: "a b", key: true, want: `a\ b`},
+		{val: "a b", want: "a b"},
+		{val: " leading", want: `\ leading`},
+		{val: "a=b:c#d!e", want: `a\=b\:c\#d\!e`},
+		{val: "a=b", key: true, want: `a\=b`},
+		{val: `C:\path`, want: `C\:\\path`},
+		{val: "l1\nl2\r\tx\fy", want: `l1\nl2\r\tx\fy`},
+		{val: "\x01", want: `\u0001`},
+		{val: "café", want: `caf\u00E9`},
+		{val: "😀", want: `\uD83D\uDE00`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.val, func(t *testing.T) {
+			if got := propertiesEscape(tt.val, tt.key); got != tt.want {
+				t.Errorf("propertiesEscape(%q, %v) = %s, want %s", tt.val, tt.key, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBundle(t *testing.T) {
+	testData := func() map[string][]byte {
+		return map[string][]byte{
+			"USER":     []byte("admin"),
+			"PASSWORD": []byte(`p@ss "word" $1`),
+			"ca.crt":   []byte("-----BEGIN-----\nMIIB\n-----END-----\n"),
+		}
+	}
+
+	tests := []struct {
+		name    string
+		data    map[string][]byte
+		params  map[string]string
+		want    map[string][]byte
+		wantErr bool
+	}{
+		{
+			name:   "env",
+			data:   testData(),
+			params: map[string]string{ParamKey: ".env", ParamFormat: bundleFormatEnv, ParamKeys: "USER,PASSWORD"},
+			want: map[string][]byte{
+				".env":   []byte("PASSWORD=\"p@ss \\\"word\\\" \\$1\"\nUSER=admin\n"),
+				"ca.crt": []byte("-----BEGIN-----\nMIIB\n-----END-----\n"),
+			},
+		},
+		{
+			name:    "env with invalid variable name",
+			data:    map[string][]byte{"1USER": []byte("admin")},
+			params:  map[string]string{ParamKey: ".env", ParamFormat: bundleFormatEnv},
+			wantErr: true,
+		},
+		{
+			name:   "properties",
+			data:   testData(),
+			params: map[string]string{ParamKey: "app.properties", ParamFormat: bundleFormatProperties},
+			want: map[string][]byte{
+				"app.properties": []byte("PASSWORD=p@ss \"word\" $1\nUSER=admin\n" +
+					`ca.crt=-----BEGIN-----\nMIIB\n-----END-----\n` + "\n"),
+			},
+		},
+		{
+			name:   "json",
+			data:   map[string][]byte{"a": []byte(`x"y\z`), "b": []byte("<1>\n")},
+			params: map[string]string{ParamKey: "config.json", ParamFormat: bundleFormatJSON},
+			want: map[string][]byte{
+				"config.json": []byte("{\n  \"a\": \"x\\\"y\\\\z\",\n  \"b\": \"\\u003c1\\u003e\\n\"\n}\n"),
+			},
+		},
+		{
+			name:   "keep keys",
+			data:   map[string][]byte{"USER": []byte("admin")},
+			params: map[string]string{ParamKey: ".env", ParamFormat: bundleFormatEnv, ParamKeepKeys: "true"},
+			want: map[string][]byte{
+				".env": []byte("USER=admin\n"),
+				"USER": []byte("admin"),
+			},
+		},
+		{
+			name:    "invalid keep keys",
+			data:    map[string][]byte{"USER": []byte("admin")},
+			params:  map[string]string{ParamKey: ".env", ParamFormat: bundleFormatEnv, ParamKeepKeys: "maybe"},
+			wantErr: true,
+		},
+		{
+			name:    "missing key param",
+			data:    map[string][]byte{"USER": []byte("admin")},
+			params:  map[string]string{ParamFormat: bundleFormatEnv},
+			wantErr: true,
+		},
+		{
+			name:    "unknown key",
+			data:    map[string][]byte{"USER": []byte("admin")},
+			params:  map[string]string{ParamKey: ".env", ParamFormat: bundleFormatEnv, ParamKeys: "TOKEN"},
+			wantErr: true,
+		},
+		{
+			name:    "invalid UTF-8",
+			data:    map[string][]byte{"BIN": {0xff, 0xfe}},
+			params:  map[string]string{ParamKey: ".env", ParamFormat: bundleFormatEnv},
+			wantErr: true,
+		},
+		{
+			name:    "unknown format",
+			data:    map[string][]byte{"USER": []byte("admin")},
+			params:  map[string]string{ParamKey: "app.ini", ParamFormat: "ini"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := bundle(tt.data, tt.params)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("bundle() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("bundle() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,123 @@
+/*
+Copyright 2025 Edenlab
+*/
+
+// Package transformer provides the registry of the named transformers of the destination data.
+//
+// A transformer is registered once, usually from an init function of the file implementing it,
+// and is referenced by its name from the steps of a destination:
+//
+//	func init() {
+//		transformer.Register("upperCase", transformer.Func(
+//			func(data map[string][]byte, params map[string]string) (map[string][]byte, error) {
+//				...
+//			}))
+//	}
+package transformer
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// Transformer converts the data of a destination, the data is owned by the pipeline
+// and may be modified in place. The params are the params of the step.
+type Transformer interface {
+	Transform(data map[string][]byte, params map[string]string) (map[string][]byte, error)
+}
+
+// Func adapts a function to the Transformer interface
+type Func func(data map[string][]byte, params map[string]string) (map[string][]byte, error)
+
+func (f Func) Transform(data map[string][]byte, params map[string]string) (map[string][]byte, error) {
+	return f(data, params)
+}
+
+// Step is a single invocation of a named transformer
+type Step struct {
+	Name   string
+	Params map[string]string
+}
+
+// StepError reports the failed step of a pipeline
+type StepError struct {
+	// Index of the step in the pipeline
+	Index int
+	Name  string
+	Err   error
+}
+
+func (e *StepError) Error() string {
+	return fmt.Sprintf("step %d (%s): %s", e.Index, e.Name, e.Err)
+}
+
+func (e *StepError) Unwrap() error {
+	return e.Err
+}
+
+var (
+	mu       sync.RWMutex
+	registry = make(map[string]Transformer)
+)
+
+// Register adds the transformer under the name, registering a name twice panics
+func Register(name string, t Transformer) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	if _, ok := registry[name]; ok {
+		panic(fmt.Sprintf("transformer %s is already registered", name))
+	}
+
+	registry[name] = t
+}
+
+// Get returns the transformer registered under the name
+func Get(name string) (Transformer, bool) {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	t, ok := registry[name]
+
+	return t, ok
+}
+
+// Names returns the sorted names of the registered transformers
+func Names() []string {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	names := make([]string, 0, len(registry))
+	for name := range registry {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	return names
+}
+
+// Apply runs the steps in order on a copy of the data, the first failed step stops the pipeline
+func Apply(steps []Step, data map[string][]byte) (map[string][]byte, error) {
+	result := make(map[string][]byte, len(data))
+	for key, val := range data {
+		result[key] = val
+	}
+
+	for i, step := range steps {
+		t, ok := Get(step.Name)
+		if !ok {
+			return nil, &StepError{Index: i, Name: step.Name, Err: fmt.Errorf("unknown transformer")}
+		}
+
+		transformed, err := t.Transform(result, step.Params)
+		if err != nil {
+			return nil, &StepError{Index: i, Name: step.Name, Err: err}
+		}
+
+		result = transformed
+	}
+
+	return result, nil
+}
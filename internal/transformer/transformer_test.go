@@ -0,0 +1,124 @@
+/*
+Copyright 2025 Edenlab
+*/
+
+package transformer
+
+import (
+	"errors"
+	"reflect"
+	"sort"
+	"testing"
+)
+
+var errTest = errors.New("test failure")
+
+func init() {
+	// testAppend appends the value param to the key param, the order of the steps shows in the result
+	Register("testAppend", Func(func(data map[string][]byte, params map[string]string) (map[string][]byte, error) {
+		data[params["key"]] = append(append([]byte(nil), data[params["key"]]...), params["value"]...)
+		return data, nil
+	}))
+	Register("testFail", Func(func(map[string][]byte, map[string]string) (map[string][]byte, error) {
+		return nil, errTest
+	}))
+}
+
+func TestRegister(t *testing.T) {
+	if _, ok := Get(TrimSpace); !ok {
+		t.Errorf("Get(%q) is not registered", TrimSpace)
+	}
+
+	if _, ok := Get("upperCase"); ok {
+		t.Errorf("Get(%q) is registered", "upperCase")
+	}
+
+	names := Names()
+	if !sort.StringsAreSorted(names) {
+		t.Errorf("Names() = %v, want sorted names", names)
+	}
+
+	for _, name := range []string{Base64Decode, Base64Encode, TrimSpace, HexEncode, HexDecode, Bundle} {
+		if i := sort.SearchStrings(names, name); i == len(names) || names[i] != name {
+			t.Errorf("Names() = %v, want %s", names, name)
+		}
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Errorf("Register() of %s twice does not panic", TrimSpace)
+		}
+	}()
+
+	Register(TrimSpace, Func(nil))
+}
+
+func TestApply(t *testing.T) {
+	data := map[string][]byte{"value": []byte("a")}
+
+	got, err := Apply([]Step{
+		{Name: "testAppend", Params: map[string]string{"key": "value", "value": "b"}},
+		{Name: "testAppend", Params: map[string]string{"key": "value", "value": "c"}},
+		{Name: Base64Encode, Params: map[string]string{ParamKeys: "value"}},
+	}, data)
+	if err != nil {
+		t.Fatalf("Apply() error = %v", err)
+	}
+
+	if want := map[string][]byte{"value": []byte("YWJj")}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Apply() = %q, want %q", got, want)
+	}
+
+	if string(data["value"]) != "a" {
+		t.Errorf("Apply() modified the input data to %q", data["value"])
+	}
+}
+
+func TestApplyStepError(t *testing.T) {
+	tests := []struct {
+		name      string
+		steps     []Step
+		wantIndex int
+		wantName  string
+		wantErr   error
+	}{
+		{
+			name:      "failed step",
+			steps:     []Step{{Name: TrimSpace}, {Name: "testFail"}, {Name: "testAppend"}},
+			wantIndex: 1,
+			wantName:  "testFail",
+			wantErr:   errTest,
+		},
+		{
+			name:      "unknown transformer",
+			steps:     []Step{{Name: "upperCase"}},
+			wantIndex: 0,
+			wantName:  "upperCase",
+		},
+		{
+			name:      "invalid value",
+			steps:     []Step{{Name: TrimSpace}, {Name: TrimSpace}, {Name: HexDecode}},
+			wantIndex: 2,
+			wantName:  HexDecode,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Apply(tt.steps, map[string][]byte{"value": []byte("not hex")})
+
+			var stepErr *StepError
+			if !errors.As(err, &stepErr) {
+				t.Fatalf("Apply() error = %v, want a StepError", err)
+			}
+
+			if stepErr.Index != tt.wantIndex || stepErr.Name != tt.wantName {
+				t.Errorf("Apply() failed step = %d (%s), want %d (%s)", stepErr.Index, stepErr.Name, tt.wantIndex, tt.wantName)
+			}
+
+			if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Errorf("Apply() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}